@@ -9,23 +9,54 @@ import (
 type Cell struct {
 	// Text content of a cell
 	Text string `json:"text,omitempty"`
+	// Value typed content rendered into Text with Format, like numbers or time.Time
+	Value interface{} `json:"value,omitempty"`
+	// Format format spec of Value
+	Format *ValueFormat `json:"format,omitempty"`
 	// Image image for a cell
 	Image *Image `json:"image,omitempty"`
+	// Chart data bar or sparkline drawn next to text
+	Chart *Chart `json:"chart,omitempty"`
 	// Style for cell
 	Style *Style `json:"style,omitempty"`
 	// IgnoreInlineStyle ignore inline text style parsing
 	IgnoreInlineStyle bool `json:"ignore_inline_style,omitempty"`
+	// TextFormat text format, uses table text format if not set, IgnoreInlineStyle forces Plain
+	TextFormat TextFormat `json:"text_format,omitempty"`
+	// ColSpan number of columns the cell spans
+	ColSpan int `json:"col_span,omitempty"`
+	// RowSpan number of rows the cell spans
+	RowSpan int `json:"row_span,omitempty"`
+	// decimalBefore/decimalAfter widest parts before and after decimal separator in column of a DECIMAL aligned cell
+	decimalBefore int
+	decimalAfter  int
+	// hyphenator hyphenator of wrapped text, from table options
+	hyphenator Hyphenator
 }
 
-// Draw render cell to image
-func (c Cell) Draw(img *image.RGBA, bounds image.Rectangle) {
+// Span returns columns/rows the cell covers, at least 1x1
+func (c Cell) Span() image.Point {
+	span := image.Pt(c.ColSpan, c.RowSpan)
+	if span.X < 1 {
+		span.X = 1
+	}
+	if span.Y < 1 {
+		span.Y = 1
+	}
+	return span
+}
+
+// Draw render cell with renderer, content is clipped to cell bounds
+func (c Cell) Draw(r Renderer, bounds image.Rectangle) {
 	if c.Style == nil {
 		return
 	}
-	c.drawBorderAndBg(img, bounds)
+	c.drawBorderAndBg(r, bounds)
 	if c.Style.Font == nil {
 		return
 	}
+	r.PushClip(bounds)
+	defer r.PopClip()
 	var (
 		imgSize    image.Point
 		imgXOffset int
@@ -36,12 +67,13 @@ func (c Cell) Draw(img *image.RGBA, bounds image.Rectangle) {
 			imgXOffset = imgSize.X
 		}
 	}
-	lineHeight := stringHeight(c.Style.Font.Size, c.Style.LineHeight)
-	lines, _ := c.Wrap(imgXOffset)
-	innerBounds := c.InnerBounds(bounds)
+	chartSize := c.ChartSize()
+	lines, _ := c.Wrap(c.textOffset())
+	lineHeights := c.lineHeights(lines)
+	innerBounds := c.drawChart(r, c.InnerBounds(bounds), chartSize)
 	var (
 		textStartX int
-		textHeight = len(lines) * lineHeight
+		textHeight = sumInts(lineHeights)
 		y          int
 	)
 	switch c.Style.VAlign {
@@ -53,20 +85,20 @@ func (c Cell) Draw(img *image.RGBA, bounds image.Rectangle) {
 	default:
 		y = innerBounds.Min.Y
 	}
-	textStartX, y = c.drawImage(img, y, textHeight, imgSize, innerBounds)
-	c.drawText(img, lines, textStartX, imgXOffset, y, lineHeight, innerBounds)
+	textStartX, y = c.drawImage(r, y, textHeight, imgSize, innerBounds)
+	c.drawText(r, lines, textStartX, imgXOffset, y, lineHeights, innerBounds)
 }
 
-func (c Cell) drawBorderAndBg(img *image.RGBA, bounds image.Rectangle) {
+func (c Cell) drawBorderAndBg(r Renderer, bounds image.Rectangle) {
 	if c.Style.BgColor != "" {
-		drawRect(img, bounds, "", c.Style.BgColor, 0)
+		r.FillRect(bounds, c.Style.BgColor)
 	}
 	if c.Style.Border != nil {
-		c.Style.Border.Draw(img, bounds)
+		c.Style.Border.Draw(r, bounds)
 	}
 }
 
-func (c Cell) drawImage(img *image.RGBA, y int, textHeight int, imgSize image.Point, innerBounds image.Rectangle) (int, int) {
+func (c Cell) drawImage(r Renderer, y int, textHeight int, imgSize image.Point, innerBounds image.Rectangle) (int, int) {
 	if c.Image == nil || c.Image.Data == nil {
 		return 0, y
 	}
@@ -82,10 +114,35 @@ func (c Cell) drawImage(img *image.RGBA, y int, textHeight int, imgSize image.Po
 		imgX, imgY, y = calcImageAlign(c.Image.Align, c.Style.VAlign, imgX, imgY, y, textHeight, imgSize, innerBounds)
 	}
 	pt := image.Pt(imgX, imgY)
-	drawImage(img, c.Image, pt)
+	r.DrawImage(c.Image, pt)
 	return textStartX, y
 }
 
+// drawChart draw chart beside text, returns the inner bounds left for image and text
+func (c Cell) drawChart(r Renderer, innerBounds image.Rectangle, chartSize image.Point) image.Rectangle {
+	if c.Chart == nil {
+		return innerBounds
+	}
+	var x, y int
+	switch c.Style.VAlign {
+	case MIDDLE:
+		y = innerBounds.Min.Y + (innerBounds.Dy()-chartSize.Y)/2
+	case BOTTOM:
+		y = innerBounds.Max.Y - chartSize.Y
+	default:
+		y = innerBounds.Min.Y
+	}
+	if c.Chart.Align == LEFT {
+		x = innerBounds.Min.X
+		innerBounds.Min.X += chartSize.X
+	} else {
+		x = innerBounds.Max.X - chartSize.X
+		innerBounds.Max.X -= chartSize.X
+	}
+	c.Chart.Draw(r, image.Rect(x, y, x+chartSize.X, y+chartSize.Y))
+	return innerBounds
+}
+
 func calcImageVAlign(valign VAlign, align Align, imgX int, imgY int, y int, textHeight int, imgSize image.Point, innerBounds image.Rectangle) (int, int, int) {
 	if valign == TOP {
 		imgY += y
@@ -126,43 +183,160 @@ func calcImageAlign(align Align, valign VAlign, imgX int, imgY int, y int, textH
 	return imgX, imgY, y
 }
 
-func (c Cell) drawText(img *image.RGBA, lines []Word, textStartX int, imgXOffset int, y int, lineHeight int, innerBounds image.Rectangle) {
-	for _, line := range lines {
+func (c Cell) drawText(r Renderer, lines []Word, textStartX int, imgXOffset int, y int, lineHeights []int, innerBounds image.Rectangle) {
+	var (
+		decimalBefore = c.decimalBefore
+		decimalAfter  = c.decimalAfter
+		faces         = make(faceCache)
+	)
+	if c.Style.Align == DECIMAL && decimalBefore+decimalAfter == 0 {
+		decimalBefore, decimalAfter = c.decimalWidths(lines)
+	}
+	rightX := innerBounds.Max.X
+	if textStartX == 0 {
+		rightX -= imgXOffset
+	}
+	for idx, line := range lines {
+		lineHeight := lineHeights[idx]
+		baseline := int(c.lineFontSize(line))
 		var x int
 		switch c.Style.Align {
 		case RIGHT:
-			if textStartX > 0 {
-				x = innerBounds.Max.X - line.Width()
-			} else {
-				x = innerBounds.Max.X - line.Width() - imgXOffset
+			x = rightX - line.Width()
+		case DECIMAL:
+			before, _, ok := decimalSplit(line, c.decimalSeparator(), faces)
+			if !ok {
+				x = rightX - line.Width()
+				break
 			}
+			// separators line up at decimalAfter left of the right edge
+			x = rightX - decimalAfter - before
 		case CENTER:
 			center := (innerBounds.Dx() - line.Width() - imgXOffset) / 2
 			x = innerBounds.Min.X + center
 		default:
 			x = innerBounds.Min.X + textStartX
 		}
+		var (
+			runs = []Text(line)
+			gaps []int
+		)
+		if c.Style.Align == JUSTIFY && idx < len(lines)-1 && !line.hardBreak() {
+			runs, gaps = justifyLine(line, rightX-x, faces)
+		}
 		pt := image.Pt(x, y)
-		for _, txt := range line {
+		for runIdx, txt := range runs {
+			if txt.Value == "\n" {
+				continue
+			}
 			if txt.Color == "" {
 				txt.Color = c.Style.Color
 			}
 			txtBounds := image.Rect(pt.X, pt.Y, pt.X+txt.Width, pt.Y+lineHeight)
-			drawText(img, txtBounds, &txt, c.Style.Font)
+			drawText(r, txtBounds, &txt, c.Style.Font, baseline)
 			pt = pt.Add(image.Pt(txt.Width, 0))
+			if gaps != nil {
+				pt = pt.Add(image.Pt(gaps[runIdx], 0))
+			}
 		}
 		y += lineHeight
 	}
 }
 
+// Validate check inline markup of cell text, returns *MarkupError if it's malformed
+func (c Cell) Validate() error {
+	if c.textFormat() != InlineTags {
+		return nil
+	}
+	_, err := ParseMarkup(c.Text)
+	return err
+}
+
+// renderValue render Value into Text before the cell is measured, rendered values are plain text by default
+func (c Cell) renderValue() Cell {
+	if c.Value == nil {
+		return c
+	}
+	var format ValueFormat
+	if c.Format != nil {
+		format = *c.Format
+	}
+	c.Text = format.FormatValue(c.Value)
+	if c.TextFormat == UnknownTextFormat {
+		c.TextFormat = Plain
+	}
+	return c
+}
+
+// numericValue numeric Value, or number parsed from Text with separators of value format locale
+func (c Cell) numericValue() (float64, bool) {
+	if c.Value != nil {
+		if n, ok := numberValue(c.Value); ok {
+			return n.float, true
+		}
+	}
+	if c.Format != nil {
+		return parseLocaleNumber(c.Text, numberLocale(c.Format.Locale))
+	}
+	return parseNumber(c.Text)
+}
+
+// textFormat resolved text format of cell
+func (c Cell) textFormat() TextFormat {
+	if c.IgnoreInlineStyle {
+		return Plain
+	}
+	if c.TextFormat == UnknownTextFormat {
+		return InlineTags
+	}
+	return c.TextFormat
+}
+
 // Wrap wraps cell content returns paragraphs, and max content width
 func (c Cell) Wrap(xOffset int) ([]Word, int) {
 	if c.Style == nil || c.Style.Font == nil {
 		return nil, 0
 	}
 	maxWidth := c.Style.MaxWidth - xOffset
-	fontFace := newFontFace(c.Style.Font.Font, c.Style.Font.Size)
-	return wrap(c.Text, maxWidth, fontFace, c.IgnoreInlineStyle)
+	return wrap(c.Text, maxWidth, c.Style.Font, c.textFormat(), c.hyphenator)
+}
+
+// lineFontSize the largest font size of text runs in line
+func (c Cell) lineFontSize(line Word) float64 {
+	size := c.Style.Font.Size
+	for _, txt := range line {
+		if txt.Font != nil && txt.Font.Size > size {
+			size = txt.Font.Size
+		}
+	}
+	return size
+}
+
+// lineHeights height of each wrapped line, based on the largest font size of its runs
+func (c Cell) lineHeights(lines []Word) []int {
+	heights := make([]int, len(lines))
+	for idx, line := range lines {
+		heights[idx] = stringHeight(c.lineFontSize(line), c.Style.LineHeight)
+	}
+	return heights
+}
+
+// ChartSize get chart size with padding
+func (c Cell) ChartSize() image.Point {
+	if c.Style == nil || c.Style.Font == nil || c.Chart == nil {
+		return image.ZP
+	}
+	return c.Chart.BoundSize(stringHeight(c.Style.Font.Size, c.Style.LineHeight))
+}
+
+// minWidth minimum cell width to fit the longest text between line break opportunities without breaking it
+func (c Cell) minWidth() int {
+	if c.Style == nil || c.Style.Font == nil {
+		return 0
+	}
+	faces := make(faceCache)
+	maxWidth := unitsMinWidth(textUnits(c.Text, c.Style.Font, c.textFormat(), faces))
+	return maxWidth + c.ChartSize().X + c.Style.BorderSize().X
 }
 
 // ImageSize get image size, will update Image.Size based on max width setting
@@ -179,6 +353,15 @@ func (c Cell) ImageSize() image.Point {
 	return c.Image.BoundSize()
 }
 
+// textOffset width taken from text lines by left or right aligned image and chart
+func (c Cell) textOffset() int {
+	var offset int
+	if c.Image != nil && c.Image.Data != nil && (c.Image.Align == LEFT || c.Image.Align == RIGHT) {
+		offset = c.ImageSize().X
+	}
+	return offset + c.ChartSize().X
+}
+
 // Size returns cell width/height
 func (c Cell) Size() image.Point {
 	var (
@@ -200,13 +383,21 @@ func (c Cell) Size() image.Point {
 			imgW = imgSize.X
 		}
 	}
+	if chartSize := c.ChartSize(); chartSize.X > 0 {
+		xOffset += chartSize.X
+		if chartSize.Y > imgH {
+			imgH = chartSize.Y
+		}
+	}
 	lines, maxWidth := c.Wrap(xOffset)
 	if maxWidth < imgW {
 		maxWidth = imgW
 	}
+	if c.Style.Align == DECIMAL && maxWidth < c.decimalBefore+c.decimalAfter {
+		maxWidth = c.decimalBefore + c.decimalAfter
+	}
 	x := maxWidth + c.Style.BorderSize().X
-	lineHeight := stringHeight(c.Style.Font.Size, c.Style.LineHeight)
-	textHeight := len(lines) * lineHeight
+	textHeight := sumInts(c.lineHeights(lines))
 	if textHeight < imgH {
 		textHeight = imgH
 	}
@@ -253,4 +444,6 @@ type Row struct {
 	Cells []Cell `json:"cells,omitempty"`
 	// Style for row
 	Style *Style `json:"style,omitempty"`
+	// Header header row, inherits header style and repeats on every page
+	Header bool `json:"header,omitempty"`
 }
@@ -0,0 +1,209 @@
+package tableimage
+
+import (
+	"image"
+	"math"
+)
+
+// ChartType in cell chart type
+type ChartType int
+
+const (
+	// UnknownChart unknown chart type
+	UnknownChart ChartType = iota
+	// DataBar horizontal bar proportional to Value
+	DataBar
+	// Sparkline line chart of Series
+	Sparkline
+	// BarSparkline bar chart of Series
+	BarSparkline
+)
+
+// Chart mini visualisation drawn in a cell next to text
+type Chart struct {
+	// Type chart type
+	Type ChartType `json:"type,omitempty"`
+	// Value data bar value
+	Value float64 `json:"value,omitempty"`
+	// Series sparkline values
+	Series []float64 `json:"series,omitempty"`
+	// Min minimum value, 0 or series minimum by default, data bars share the minimum of their column
+	Min *float64 `json:"min,omitempty"`
+	// Max maximum value, series maximum by default, data bars share the maximum of their column
+	Max *float64 `json:"max,omitempty"`
+	// Color bar/line color
+	Color string `json:"color,omitempty"`
+	// NegativeColor color for negative values, Color by default
+	NegativeColor string `json:"negative_color,omitempty"`
+	// BgColor chart background color
+	BgColor string `json:"bg_color,omitempty"`
+	// LineWidth sparkline width
+	LineWidth int `json:"line_width,omitempty"`
+	// Size chart width/height, DefaultChartWidth and line height by default
+	Size image.Point `json:"size,omitempty"`
+	// Align chart position, LEFT or RIGHT of the text, RIGHT by default
+	Align Align `json:"align,omitempty"`
+	// Padding chart padding
+	Padding *Padding `json:"padding,omitempty"`
+}
+
+// BoundSize chart size with padding, lineHeight is used as default height
+func (c Chart) BoundSize(lineHeight int) image.Point {
+	size := c.Size
+	if size.X <= 0 {
+		size.X = DefaultChartWidth
+	}
+	if size.Y <= 0 {
+		size.Y = lineHeight
+	}
+	if c.Padding != nil {
+		size = size.Add(c.Padding.Size())
+	}
+	return size
+}
+
+// Draw render chart in bounds
+func (c Chart) Draw(r Renderer, bounds image.Rectangle) {
+	if c.Padding != nil {
+		bounds = image.Rect(bounds.Min.X+c.Padding.Left, bounds.Min.Y+c.Padding.Top, bounds.Max.X-c.Padding.Right, bounds.Max.Y-c.Padding.Bottom)
+	}
+	if bounds.Empty() {
+		return
+	}
+	if c.BgColor != "" {
+		r.FillRect(bounds, c.BgColor)
+	}
+	switch c.Type {
+	case DataBar:
+		c.drawDataBar(r, bounds)
+	case Sparkline:
+		c.drawSparkline(r, bounds)
+	case BarSparkline:
+		c.drawBarSparkline(r, bounds)
+	}
+}
+
+func (c Chart) drawDataBar(r Renderer, bounds image.Rectangle) {
+	min, max := c.valueRange([]float64{c.Value}, true)
+	zero := c.scaleX(0, min, max, bounds)
+	x := c.scaleX(c.Value, min, max, bounds)
+	if x < zero {
+		r.FillRect(image.Rect(x, bounds.Min.Y, zero, bounds.Max.Y), c.color(c.Value))
+	} else if x > zero {
+		r.FillRect(image.Rect(zero, bounds.Min.Y, x, bounds.Max.Y), c.color(c.Value))
+	}
+}
+
+func (c Chart) drawSparkline(r Renderer, bounds image.Rectangle) {
+	if len(c.Series) == 0 {
+		return
+	}
+	min, max := c.valueRange(c.Series, false)
+	width := c.LineWidth
+	if width <= 0 {
+		width = DefaultBorderWidth
+	}
+	step := float64(bounds.Dx()) / math.Max(1, float64(len(c.Series)-1))
+	var last image.Point
+	for idx, v := range c.Series {
+		pt := image.Pt(bounds.Min.X+int(math.Round(step*float64(idx))), c.scaleY(v, min, max, bounds))
+		if idx > 0 {
+			r.StrokeLine(last, pt, c.color(0), width)
+		}
+		last = pt
+	}
+}
+
+func (c Chart) drawBarSparkline(r Renderer, bounds image.Rectangle) {
+	if len(c.Series) == 0 {
+		return
+	}
+	min, max := c.valueRange(c.Series, true)
+	zero := c.scaleY(0, min, max, bounds)
+	barWidth := float64(bounds.Dx()) / float64(len(c.Series))
+	for idx, v := range c.Series {
+		x0 := bounds.Min.X + int(math.Round(barWidth*float64(idx)))
+		x1 := bounds.Min.X + int(math.Round(barWidth*float64(idx+1)))
+		if x1-x0 > 2 {
+			x1--
+		}
+		y := c.scaleY(v, min, max, bounds)
+		if y < zero {
+			r.FillRect(image.Rect(x0, y, x1, zero), c.color(v))
+		} else {
+			r.FillRect(image.Rect(x0, zero, x1, y), c.color(v))
+		}
+	}
+}
+
+// dataBarRanges values range of data bars in columns including zero baseline
+func dataBarRanges(rows []Row, cols [][]int) map[int][2]float64 {
+	ranges := make(map[int][2]float64)
+	for rowIdx, row := range rows {
+		for cellIdx, cell := range row.Cells {
+			if cell.Chart == nil || cell.Chart.Type != DataBar {
+				continue
+			}
+			colIdx := cols[rowIdx][cellIdx]
+			r := ranges[colIdx]
+			r[0] = math.Min(r[0], cell.Chart.Value)
+			r[1] = math.Max(r[1], cell.Chart.Value)
+			ranges[colIdx] = r
+		}
+	}
+	return ranges
+}
+
+// applyDataBarRange set missing min/max of data bar to its column range, so that bars of a column share their scale
+func (c Cell) applyDataBarRange(colIdx int, ranges map[int][2]float64) Cell {
+	if c.Chart == nil || c.Chart.Type != DataBar || (c.Chart.Min != nil && c.Chart.Max != nil) {
+		return c
+	}
+	r := ranges[colIdx]
+	chart := *c.Chart
+	if chart.Min == nil {
+		chart.Min = &r[0]
+	}
+	if chart.Max == nil {
+		chart.Max = &r[1]
+	}
+	c.Chart = &chart
+	return c
+}
+
+// valueRange chart min/max, bars include zero baseline
+func (c Chart) valueRange(values []float64, withZero bool) (float64, float64) {
+	min, max := values[0], values[0]
+	if withZero {
+		min, max = 0, 0
+	}
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	if c.Min != nil {
+		min = *c.Min
+	}
+	if c.Max != nil {
+		max = *c.Max
+	}
+	return min, max
+}
+
+func (c Chart) scaleX(v float64, min float64, max float64, bounds image.Rectangle) int {
+	return bounds.Min.X + int(math.Round(scaleRatio(v, min, max)*float64(bounds.Dx())))
+}
+
+func (c Chart) scaleY(v float64, min float64, max float64, bounds image.Rectangle) int {
+	return bounds.Max.Y - int(math.Round(scaleRatio(v, min, max)*float64(bounds.Dy())))
+}
+
+func (c Chart) color(v float64) string {
+	if v < 0 && c.NegativeColor != "" {
+		return c.NegativeColor
+	}
+	if c.Color == "" {
+		return DefaultChartColor
+	}
+	return c.Color
+}
@@ -0,0 +1,81 @@
+// Command tableimage-server serves table images rendered from JSON table documents over HTTP
+//
+// Usage:
+//
+//	tableimage-server [flags]
+//
+// POST a document to / and get the image back, GET /healthz for health checks.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bububa/tableimage"
+	"github.com/bububa/tableimage/server"
+	"github.com/llgcode/draw2d"
+)
+
+func main() {
+	log.SetFlags(log.LstdFlags)
+	log.SetPrefix("tableimage-server: ")
+	var (
+		addr         = flag.String("addr", ":8080", "listen address")
+		fontFolder   = flag.String("font-folder", "", "folder of font files, required")
+		fontName     = flag.String("font", "Roboto", "default font name, files are looked up in font folder like Robotosr.ttf")
+		dpi          = flag.Int("dpi", tableimage.DefaultDPI, "default font dpi")
+		maxBodySize  = flag.Int64("max-body-size", server.DefaultMaxBodySize, "max request body size in bytes")
+		maxRows      = flag.Int("max-rows", server.DefaultMaxRows, "max rows of a document")
+		maxColumns   = flag.Int("max-columns", server.DefaultMaxColumns, "max columns of a document")
+		maxPixels    = flag.Int("max-pixels", server.DefaultMaxPixels, "max width * height of rendered image")
+		remoteImages = flag.Bool("remote-images", false, "allow downloading cell images from remote links")
+	)
+	flag.Parse()
+	if *fontFolder == "" {
+		log.Fatalln("-font-folder is required")
+	}
+	options := []server.Option{
+		server.WithMaxBodySize(*maxBodySize),
+		server.WithMaxRows(*maxRows),
+		server.WithMaxColumns(*maxColumns),
+		server.WithMaxPixels(*maxPixels),
+		server.WithFontFolder(*fontFolder),
+		server.WithTableImageOptions(
+			tableimage.WithFontData(&draw2d.FontData{Name: *fontName, Family: draw2d.FontFamilySans}),
+			tableimage.WithDPI(*dpi),
+		),
+	}
+	if *remoteImages {
+		options = append(options, server.WithRemoteImages())
+	}
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(options...),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Println(err)
+		}
+	}()
+	log.Printf("listening on %s\n", *addr)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatalln(err)
+	}
+	<-done
+}
@@ -0,0 +1,349 @@
+// Command tableimage renders tables from CSV, TSV, Markdown, JSON or YAML documents into images
+//
+// Usage:
+//
+//	tableimage [flags] [input]
+//
+// Input is read from stdin when omitted or "-", output is written to stdout unless -o is set.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bububa/tableimage"
+	"github.com/llgcode/draw2d"
+)
+
+type config struct {
+	from        string
+	output      string
+	format      string
+	fontFolder  string
+	fontName    string
+	fontFamily  string
+	fontStyle   string
+	fontSize    float64
+	dpi         int
+	color       string
+	bgColor     string
+	borderColor string
+	width       int
+	textFormat  string
+	header      string
+	delimiter   string
+	caption     string
+	footer      string
+	set         map[string]bool
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("tableimage: ")
+	cfg := &config{}
+	flag.StringVar(&cfg.from, "from", "", "input format: csv, tsv, markdown, json or yaml, detected from file extension or content by default")
+	flag.StringVar(&cfg.output, "o", "", "output file, stdout by default")
+	flag.StringVar(&cfg.format, "format", "", "output format: png, jpeg, svg or pdf, detected from output file extension, png by default")
+	flag.StringVar(&cfg.fontFolder, "font-folder", "", "folder of font files, required unless the document sets font_folder")
+	flag.StringVar(&cfg.fontName, "font", "Roboto", "font name, files are looked up in font folder like Robotosr.ttf")
+	flag.StringVar(&cfg.fontFamily, "font-family", "sans", "font family: sans, serif or mono")
+	flag.StringVar(&cfg.fontStyle, "font-style", "normal", "font style: normal, bold, italic or bold_italic")
+	flag.Float64Var(&cfg.fontSize, "font-size", tableimage.DefaultFontSize, "font size")
+	flag.IntVar(&cfg.dpi, "dpi", tableimage.DefaultDPI, "font dpi")
+	flag.StringVar(&cfg.color, "color", "", "text color")
+	flag.StringVar(&cfg.bgColor, "bg-color", "#FFFFFF", "background color")
+	flag.StringVar(&cfg.borderColor, "border-color", "", "border color")
+	flag.IntVar(&cfg.width, "width", 0, "exact table width in pixels")
+	flag.StringVar(&cfg.textFormat, "text-format", "", "cell text format: plain, inline_tags or markdown")
+	flag.StringVar(&cfg.header, "header", "auto", "csv header row: auto, yes or no")
+	flag.StringVar(&cfg.delimiter, "delimiter", "", "csv field delimiter, ',' for csv and tab for tsv by default")
+	flag.StringVar(&cfg.caption, "caption", "", "table caption")
+	flag.StringVar(&cfg.footer, "footer", "", "table footer")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [input]\n\nRender CSV, TSV, Markdown, JSON or YAML tables into images.\n\nFlags:\n", filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	cfg.set = make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		cfg.set[f.Name] = true
+	})
+	if err := run(cfg, flag.Arg(0)); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func run(cfg *config, input string) error {
+	data, err := readInput(input)
+	if err != nil {
+		return err
+	}
+	from := cfg.from
+	if from == "" {
+		from = detectFormat(input, data)
+	}
+	doc, err := loadDocument(cfg, from, data)
+	if err != nil {
+		return err
+	}
+	if cfg.fontFolder == "" && doc.FontFolder == "" {
+		return errors.New("no font folder, set -font-folder or font_folder in document")
+	}
+	options, err := cfg.options(from, doc)
+	if err != nil {
+		return err
+	}
+	if err := cfg.applyDocument(doc); err != nil {
+		return err
+	}
+	if cfg.output == "" || cfg.output == "-" {
+		var buf bytes.Buffer
+		if err := doc.Write(&buf, options...); err != nil {
+			return err
+		}
+		_, err := buf.WriteTo(os.Stdout)
+		return err
+	}
+	return writeFile(cfg.output, func(w io.Writer) error {
+		return doc.Write(w, options...)
+	})
+}
+
+// writeFile write output into a temporary file which replaces file name on success, failed renders leave no partial file
+func writeFile(name string, write func(io.Writer) error) error {
+	f, err := ioutil.TempFile(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	err = write(f)
+	if err == nil {
+		err = f.Chmod(0644)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, name)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+func readInput(input string) ([]byte, error) {
+	if input == "" || input == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(input)
+}
+
+var yamlRows = regexp.MustCompile(`(?m)^rows:`)
+
+// detectFormat detect input format from file extension, or from content for stdin
+func detectFormat(input string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(input)) {
+	case ".csv":
+		return "csv"
+	case ".tsv", ".tab":
+		return "tsv"
+	case ".md", ".markdown":
+		return "markdown"
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return "json"
+	}
+	// yaml documents start with a document marker or have top level rows, other input mentioning rows: is text
+	if bytes.HasPrefix(trimmed, []byte("---")) || yamlRows.Match(trimmed) {
+		if _, err := tableimage.ParseDocument(data); err == nil {
+			return "yaml"
+		}
+	}
+	if tables, err := tableimage.ParseMarkdownTables(bytes.NewReader(data)); err == nil && len(tables) > 0 {
+		return "markdown"
+	}
+	if line := firstLine(trimmed); bytes.Count(line, []byte("\t")) > bytes.Count(line, []byte(",")) {
+		return "tsv"
+	}
+	return "csv"
+}
+
+func firstLine(data []byte) []byte {
+	if idx := bytes.IndexByte(data, '\n'); idx >= 0 {
+		return data[:idx]
+	}
+	return data
+}
+
+// loadDocument load input into document
+func loadDocument(cfg *config, from string, data []byte) (*tableimage.Document, error) {
+	doc := new(tableimage.Document)
+	switch from {
+	case "json", "yaml":
+		var err error
+		if doc, err = tableimage.ParseDocument(data); err != nil {
+			return nil, err
+		}
+	case "markdown":
+		table, err := tableimage.ParseMarkdownTable(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		doc.Rows = table.Rows
+		doc.Caption = table.Caption
+	case "csv", "tsv":
+		options, err := cfg.csvOptions()
+		if err != nil {
+			return nil, err
+		}
+		read := tableimage.ReadCSV
+		if from == "tsv" {
+			read = tableimage.ReadTSV
+		}
+		if doc.Rows, err = read(bytes.NewReader(data), options...); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown input format %q", from)
+	}
+	if len(doc.Rows) == 0 {
+		return nil, errors.New("no rows in input")
+	}
+	return doc, nil
+}
+
+func (cfg *config) csvOptions() ([]tableimage.CSVOption, error) {
+	var options []tableimage.CSVOption
+	if cfg.delimiter != "" {
+		delimiter := []rune(strings.Replace(cfg.delimiter, `\t`, "\t", 1))
+		if len(delimiter) != 1 {
+			return nil, fmt.Errorf("invalid delimiter %q", cfg.delimiter)
+		}
+		options = append(options, tableimage.WithCSVDelimiter(delimiter[0]))
+	}
+	switch cfg.header {
+	case "auto":
+	case "yes":
+		options = append(options, tableimage.WithCSVHeader(tableimage.FirstRowHeader))
+	case "no":
+		options = append(options, tableimage.WithCSVHeader(tableimage.NoHeader))
+	default:
+		return nil, fmt.Errorf("invalid header %q", cfg.header)
+	}
+	return options, nil
+}
+
+// options table image options from flags, documents keep their own settings unless flags are set explicitly,
+// documents without font data use the font flags
+func (cfg *config) options(from string, doc *tableimage.Document) ([]tableimage.Option, error) {
+	isDocument := from == "json" || from == "yaml"
+	apply := func(name string) bool {
+		return !isDocument || cfg.set[name]
+	}
+	var options []tableimage.Option
+	if cfg.fontFolder != "" {
+		options = append(options, tableimage.WithFontFolder(cfg.fontFolder))
+	}
+	hasFont := doc.Style != nil && doc.Style.Font != nil && doc.Style.Font.Data != nil
+	if !hasFont || apply("font") || apply("font-family") || apply("font-style") {
+		data, err := cfg.fontData()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, tableimage.WithFontData(data))
+	}
+	if apply("font-size") {
+		options = append(options, tableimage.WithFontSize(cfg.fontSize))
+	}
+	if apply("dpi") {
+		options = append(options, tableimage.WithDPI(cfg.dpi))
+	}
+	if cfg.color != "" {
+		options = append(options, tableimage.WithColor(cfg.color))
+	}
+	if apply("bg-color") {
+		options = append(options, tableimage.WithBgColor(cfg.bgColor))
+	}
+	if cfg.borderColor != "" {
+		options = append(options, tableimage.WithBorderColor(cfg.borderColor))
+	}
+	if cfg.width > 0 {
+		options = append(options, tableimage.WithTableWidth(cfg.width))
+	}
+	if cfg.textFormat != "" {
+		var format tableimage.TextFormat
+		if err := format.UnmarshalText([]byte(cfg.textFormat)); err != nil {
+			return nil, err
+		}
+		options = append(options, tableimage.WithTextFormat(format))
+	}
+	return options, nil
+}
+
+func (cfg *config) fontData() (*draw2d.FontData, error) {
+	data := &draw2d.FontData{Name: cfg.fontName}
+	switch cfg.fontFamily {
+	case "sans":
+		data.Family = draw2d.FontFamilySans
+	case "serif":
+		data.Family = draw2d.FontFamilySerif
+	case "mono":
+		data.Family = draw2d.FontFamilyMono
+	default:
+		return nil, fmt.Errorf("invalid font family %q", cfg.fontFamily)
+	}
+	switch cfg.fontStyle {
+	case "normal":
+		data.Style = draw2d.FontStyleNormal
+	case "bold":
+		data.Style = draw2d.FontStyleBold
+	case "italic":
+		data.Style = draw2d.FontStyleItalic
+	case "bold_italic":
+		data.Style = draw2d.FontStyleBold | draw2d.FontStyleItalic
+	default:
+		return nil, fmt.Errorf("invalid font style %q", cfg.fontStyle)
+	}
+	return data, nil
+}
+
+// applyDocument set document image type from -format flag or output file extension, caption and footer from flags
+func (cfg *config) applyDocument(doc *tableimage.Document) error {
+	format := cfg.format
+	if format == "" {
+		switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(cfg.output), ".")); ext {
+		case "jpg", "jpeg", "png", "svg", "pdf":
+			format = ext
+		}
+	}
+	if format == "jpg" {
+		format = "jpeg"
+	}
+	if format != "" {
+		if err := doc.ImageType.UnmarshalText([]byte(format)); err != nil {
+			return err
+		}
+	}
+	if cfg.caption != "" {
+		doc.Caption = &tableimage.Cell{Text: cfg.caption}
+	}
+	if cfg.footer != "" {
+		doc.Footer = &tableimage.Cell{Text: cfg.footer}
+	}
+	return nil
+}
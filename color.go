@@ -3,17 +3,54 @@ package tableimage
 import (
 	"fmt"
 	"image/color"
+	"math"
+	"strconv"
 	"strings"
 )
 
+// namedColors css basic color keywords
+var namedColors = map[string]string{
+	"black":       "#000000",
+	"silver":      "#C0C0C0",
+	"gray":        "#808080",
+	"grey":        "#808080",
+	"white":       "#FFFFFF",
+	"maroon":      "#800000",
+	"red":         "#FF0000",
+	"purple":      "#800080",
+	"fuchsia":     "#FF00FF",
+	"green":       "#008000",
+	"lime":        "#00FF00",
+	"olive":       "#808000",
+	"yellow":      "#FFFF00",
+	"navy":        "#000080",
+	"blue":        "#0000FF",
+	"teal":        "#008080",
+	"aqua":        "#00FFFF",
+	"orange":      "#FFA500",
+	"transparent": "#00000000",
+}
+
 // ColorFromHex get color from hex
 func ColorFromHex(hexColor string) color.RGBA {
 	r, g, b, a := parseHexColor(hexColor)
 	return color.RGBA{uint8(r), uint8(g), uint8(b), uint8(a)}
 }
 
-// parseHexColor parse color hex string to rgba value
+// nrgbaFromHex get non alpha-premultiplied color from hex, ColorFromHex keeps channels as written
+// which color.RGBA consumers read as premultiplied
+func nrgbaFromHex(hexColor string) color.NRGBA {
+	return color.NRGBA(ColorFromHex(hexColor))
+}
+
+// parseHexColor parse color hex string, rgb()/rgba() function or css basic color name to rgba value
 func parseHexColor(x string) (r, g, b, a uint32) {
+	if hex, found := namedColors[strings.ToLower(x)]; found {
+		x = hex
+	}
+	if strings.HasPrefix(x, "rgb") {
+		return parseRGBColor(x)
+	}
 	if !strings.HasPrefix(x, "#") {
 		return color.Transparent.RGBA()
 	}
@@ -36,3 +73,74 @@ func parseHexColor(x string) (r, g, b, a uint32) {
 	}
 	return
 }
+
+// isColor check if s is a hex color, rgb()/rgba() function or css basic color name
+func isColor(s string) bool {
+	if _, found := namedColors[strings.ToLower(s)]; found {
+		return true
+	}
+	if strings.HasPrefix(s, "rgb") {
+		_, _, _, a := parseRGBColor(s)
+		return a > 0 || strings.Count(s, ",") == 3
+	}
+	if !strings.HasPrefix(s, "#") {
+		return false
+	}
+	hex := s[1:]
+	if len(hex) != 3 && len(hex) != 6 && len(hex) != 8 {
+		return false
+	}
+	_, err := strconv.ParseUint(hex, 16, 32)
+	return err == nil
+}
+
+// parseRGBColor parse rgb(r, g, b) or rgba(r, g, b, a) color, a in [0, 1]
+func parseRGBColor(x string) (r, g, b, a uint32) {
+	start := strings.IndexByte(x, '(')
+	end := strings.LastIndexByte(x, ')')
+	if start < 0 || end < start {
+		return color.Transparent.RGBA()
+	}
+	parts := strings.Split(x[start+1:end], ",")
+	if len(parts) != 3 && len(parts) != 4 {
+		return color.Transparent.RGBA()
+	}
+	values := make([]uint32, 4)
+	values[3] = 255
+	for idx, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return color.Transparent.RGBA()
+		}
+		if idx == 3 {
+			v *= 255
+		}
+		values[idx] = uint32(math.Round(math.Max(0, math.Min(255, v))))
+	}
+	return values[0], values[1], values[2], values[3]
+}
+
+// HexFromColor get hex string of color, alpha is omitted for opaque colors
+func HexFromColor(c color.RGBA) string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02X%02X%02X%02X", c.R, c.G, c.B, c.A)
+}
+
+// interpolateColor linear interpolate between colors a and b, t in [0, 1]
+func interpolateColor(a color.RGBA, b color.RGBA, t float64) color.RGBA {
+	mix := func(x uint8, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t))
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), mix(a.A, b.A)}
+}
+
+// contrastColor readable text color on background c
+func contrastColor(c color.RGBA) string {
+	luminance := (0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)) / 255
+	if luminance > 0.5 {
+		return DefaultColor
+	}
+	return "#FFFFFF"
+}
@@ -0,0 +1,255 @@
+package tableimage
+
+import (
+	"math"
+)
+
+// ColumnSpec column width specification
+type ColumnSpec struct {
+	// Width fixed width in pixels
+	Width int `json:"width,omitempty"`
+	// Percent width in percentage of table rows width
+	Percent float64 `json:"percent,omitempty"`
+	// MinWidth minimum width in pixels
+	MinWidth int `json:"min_width,omitempty"`
+	// MaxWidth maximum width in pixels
+	MaxWidth int `json:"max_width,omitempty"`
+	// Flex weight to share the table width left by other columns, requires table width
+	Flex float64 `json:"flex,omitempty"`
+	// Style column style, inherited by cells in column between row and cell styles
+	Style *Style `json:"style,omitempty"`
+	// ColorScale background color scale for numeric cells, takes precedence over rules
+	ColorScale *ColorScale `json:"color_scale,omitempty"`
+}
+
+// IsZero check if spec has no width setting, such a column is sized by content
+func (c ColumnSpec) IsZero() bool {
+	return c.Width == 0 && c.Percent < 1e-15 && c.MinWidth == 0 && c.MaxWidth == 0 && c.Flex < 1e-15
+}
+
+func (c ColumnSpec) clamp(width int) int {
+	if c.MaxWidth > 0 && width > c.MaxWidth {
+		width = c.MaxWidth
+	}
+	if width < c.MinWidth {
+		width = c.MinWidth
+	}
+	return width
+}
+
+// rowsWidth target rows width without table border, 0 for content sized table
+func (ti *TableImage) rowsWidth() int {
+	if ti.tableWidth <= 0 {
+		return 0
+	}
+	width := ti.tableWidth - ti.BorderSize().X
+	if width < 0 {
+		return 0
+	}
+	return width
+}
+
+// resolveColumns apply column specs and table width to content based widths in place,
+// returns which columns got resolved widths or nil if widths are content based
+func (ti *TableImage) resolveColumns(rows []Row, cols [][]int, widths []int) []bool {
+	if len(ti.columns) == 0 && ti.rowsWidth() == 0 {
+		return nil
+	}
+	var (
+		resolved  = make([]bool, len(widths))
+		fixed     = make([]bool, len(widths))
+		flexTotal float64
+		used      int
+		base      = ti.rowsWidth()
+	)
+	if base == 0 {
+		base = sumInts(widths)
+	}
+	for idx := range widths {
+		resolved[idx] = ti.rowsWidth() > 0
+		if idx >= len(ti.columns) || ti.columns[idx].IsZero() {
+			used += widths[idx]
+			continue
+		}
+		spec := ti.columns[idx]
+		resolved[idx] = true
+		switch {
+		case spec.Flex > 0 && ti.rowsWidth() > 0:
+			flexTotal += spec.Flex
+			fixed[idx] = true
+			continue
+		case spec.Width > 0:
+			widths[idx] = spec.Width
+			fixed[idx] = true
+		case spec.Percent > 0:
+			widths[idx] = int(math.Round(float64(base) * spec.Percent / 100))
+			fixed[idx] = true
+		}
+		widths[idx] = spec.clamp(widths[idx])
+		used += widths[idx]
+	}
+	if flexTotal > 0 {
+		remain := ti.rowsWidth() - used
+		if remain < 0 {
+			remain = 0
+		}
+		for idx := range widths {
+			if idx >= len(ti.columns) || !resolved[idx] || ti.columns[idx].Flex <= 0 {
+				continue
+			}
+			spec := ti.columns[idx]
+			widths[idx] = spec.clamp(int(float64(remain) * spec.Flex / flexTotal))
+		}
+	}
+	if ti.rowsWidth() > 0 {
+		ti.fitColumns(widths, minColumnsWidth(rows, cols, len(widths)), fixed)
+	}
+	return resolved
+}
+
+// fitColumns grow or shrink not fixed columns proportionally so that widths sum up to table rows width,
+// columns don't shrink under their minimum word width unless there is no other way to fit
+func (ti *TableImage) fitColumns(widths []int, mins []int, fixed []bool) {
+	target := ti.rowsWidth()
+	limits := func(idx int) (int, int) {
+		var spec ColumnSpec
+		if idx < len(ti.columns) {
+			spec = ti.columns[idx]
+		}
+		min := mins[idx]
+		if spec.MinWidth > min {
+			min = spec.MinWidth
+		}
+		return min, spec.MaxWidth
+	}
+	for i := 0; i <= len(widths); i++ {
+		diff := target - sumInts(widths)
+		if diff == 0 {
+			return
+		}
+		var (
+			adjustable []int
+			weight     int
+		)
+		for idx, w := range widths {
+			min, max := limits(idx)
+			if fixed[idx] || (diff < 0 && w <= min) || (diff > 0 && max > 0 && w >= max) {
+				continue
+			}
+			adjustable = append(adjustable, idx)
+			weight += w
+		}
+		if len(adjustable) == 0 {
+			break
+		}
+		remain := diff
+		for pos, idx := range adjustable {
+			delta := diff / len(adjustable)
+			if weight > 0 {
+				delta = diff * widths[idx] / weight
+			}
+			if pos == len(adjustable)-1 {
+				delta = remain
+			}
+			w := widths[idx] + delta
+			min, max := limits(idx)
+			if max > 0 && w > max {
+				w = max
+			}
+			if w < min {
+				w = min
+			}
+			remain -= w - widths[idx]
+			widths[idx] = w
+		}
+	}
+	if diff := target - sumInts(widths); diff != 0 && len(widths) > 0 {
+		distributeEvenly(widths, diff)
+	}
+}
+
+// minColumnsWidth minimum width of each column to fit its longest word
+func minColumnsWidth(rows []Row, cols [][]int, maxCols int) []int {
+	mins := make([]int, maxCols)
+	for rowIdx, row := range rows {
+		for cellIdx, cell := range row.Cells {
+			if cell.Span().X > 1 {
+				continue
+			}
+			colIdx := cols[rowIdx][cellIdx]
+			if w := cell.minWidth(); w > mins[colIdx] {
+				mins[colIdx] = w
+			}
+		}
+	}
+	return mins
+}
+
+// distributeEvenly add diff to sizes evenly, sizes won't go below zero
+func distributeEvenly(sizes []int, diff int) {
+	each := diff / len(sizes)
+	remain := diff % len(sizes)
+	for i := range sizes {
+		sizes[i] += each
+		if i < remain {
+			sizes[i]++
+		} else if i < -remain {
+			sizes[i]--
+		}
+		if sizes[i] < 0 {
+			sizes[i] = 0
+		}
+	}
+}
+
+// columnParentStyle style inherited by a cell starting at column colIdx,
+// rowStyle is the inherited row style and rowOwnStyle the row style before inheriting from table
+func (ti *TableImage) columnParentStyle(colIdx int, rowStyle *Style, rowOwnStyle *Style, tableStyle *Style) *Style {
+	if colIdx >= len(ti.columns) || ti.columns[colIdx].Style == nil {
+		return rowStyle
+	}
+	style := *ti.columns[colIdx].Style
+	if ti.columnPrecedence != RowOverColumn {
+		style.Inherit(rowStyle, ti.fontCache)
+		return &style
+	}
+	style.Inherit(tableStyle, ti.fontCache)
+	if rowOwnStyle == nil {
+		return &style
+	}
+	ownStyle := *rowOwnStyle
+	ownStyle.Inherit(&style, ti.fontCache)
+	return &ownStyle
+}
+
+// rewrapRows wrap cells covering resolved columns to their columns width instead of cell's own MaxWidth
+func rewrapRows(rows []Row, cols [][]int, widths []int, resolved []bool) []Row {
+	ret := make([]Row, 0, len(rows))
+	for rowIdx, row := range rows {
+		cells := make([]Cell, 0, len(row.Cells))
+		for cellIdx, cell := range row.Cells {
+			var (
+				colIdx = cols[rowIdx][cellIdx]
+				span   = cell.Span()
+				width  int
+				rewrap bool
+			)
+			for i := colIdx; i < colIdx+span.X; i++ {
+				width += widths[i]
+				rewrap = rewrap || resolved[i]
+			}
+			if rewrap && cell.Style != nil {
+				style := *cell.Style
+				style.MaxWidth = width - style.BorderSize().X
+				if style.MaxWidth < 1 {
+					style.MaxWidth = 1
+				}
+				cell.Style = &style
+			}
+			cells = append(cells, cell)
+		}
+		row.Cells = cells
+		ret = append(ret, row)
+	}
+	return ret
+}
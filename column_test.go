@@ -0,0 +1,128 @@
+package tableimage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveColumns(t *testing.T) {
+	border := newTestTableImage(t).BorderSize().X
+	tests := []struct {
+		name         string
+		columns      []ColumnSpec
+		tableWidth   int
+		widths       []int
+		want         []int
+		wantResolved []bool
+	}{
+		{
+			name:   "content sized",
+			widths: []int{50, 100},
+			want:   []int{50, 100},
+		},
+		{
+			name:         "fixed width",
+			columns:      []ColumnSpec{{Width: 80}},
+			widths:       []int{50, 100},
+			want:         []int{80, 100},
+			wantResolved: []bool{true, false},
+		},
+		{
+			name:         "percent of content width",
+			columns:      []ColumnSpec{{Percent: 20}, {}},
+			widths:       []int{50, 100},
+			want:         []int{30, 100},
+			wantResolved: []bool{true, false},
+		},
+		{
+			name:         "min and max width",
+			columns:      []ColumnSpec{{MinWidth: 70}, {MaxWidth: 60}},
+			widths:       []int{50, 100},
+			want:         []int{70, 60},
+			wantResolved: []bool{true, true},
+		},
+		{
+			name:         "table width grows columns proportionally",
+			tableWidth:   300,
+			widths:       []int{50, 100},
+			want:         []int{100, 200},
+			wantResolved: []bool{true, true},
+		},
+		{
+			name:         "table width shrinks columns proportionally",
+			tableWidth:   90,
+			widths:       []int{50, 100},
+			want:         []int{30, 60},
+			wantResolved: []bool{true, true},
+		},
+		{
+			name:         "table width keeps fixed width",
+			columns:      []ColumnSpec{{Width: 100}},
+			tableWidth:   300,
+			widths:       []int{50, 100},
+			want:         []int{100, 200},
+			wantResolved: []bool{true, true},
+		},
+		{
+			name:         "percent of table width",
+			columns:      []ColumnSpec{{Percent: 50}},
+			tableWidth:   300,
+			widths:       []int{50, 100},
+			want:         []int{150, 150},
+			wantResolved: []bool{true, true},
+		},
+		{
+			name:         "table width stops at max width",
+			columns:      []ColumnSpec{{MaxWidth: 80}},
+			tableWidth:   300,
+			widths:       []int{50, 100},
+			want:         []int{80, 220},
+			wantResolved: []bool{true, true},
+		},
+		{
+			name:         "flex shares remaining width",
+			columns:      []ColumnSpec{{Width: 100}, {Flex: 1}, {Flex: 3}},
+			tableWidth:   300,
+			widths:       []int{50, 50, 50},
+			want:         []int{100, 50, 150},
+			wantResolved: []bool{true, true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := []Option{WithColumns(tt.columns...)}
+			if tt.tableWidth > 0 {
+				options = append(options, WithTableWidth(tt.tableWidth+border))
+			}
+			ti := newTestTableImage(t, options...)
+			widths := append([]int(nil), tt.widths...)
+			resolved := ti.resolveColumns(nil, nil, widths)
+			if !reflect.DeepEqual(widths, tt.want) {
+				t.Errorf("widths = %v, want %v", widths, tt.want)
+			}
+			if !reflect.DeepEqual(resolved, tt.wantResolved) {
+				t.Errorf("resolved = %v, want %v", resolved, tt.wantResolved)
+			}
+		})
+	}
+}
+
+func TestTableWidth(t *testing.T) {
+	rows := []Row{
+		{Cells: []Cell{{Text: "incomprehensibilities"}, {Text: "a few short words in a longer cell text"}}},
+		{Cells: []Cell{{Text: "b"}, {Text: "c"}}},
+	}
+	for _, width := range []int{250, 400, 800} {
+		ti := newTestTableImage(t, WithTableWidth(width))
+		table, err := NewTable(ti, rows, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ti.Size(table).X; got != width {
+			t.Errorf("table width %d: size = %d", width, got)
+		}
+		if min := table.rows[0].Cells[0].minWidth(); table.colsWidth[0] < min {
+			t.Errorf("table width %d: first column width = %d, narrower than its longest word %d", width, table.colsWidth[0], min)
+		}
+	}
+}
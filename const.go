@@ -15,6 +15,16 @@ const (
 	DefaultBorderWidth = 1
 	// DefaultDPI default font dpi
 	DefaultDPI = 72
+	// DefaultHeaderBgColor default header row background color
+	DefaultHeaderBgColor = "#F5F5F5"
+	// DefaultChartWidth default in cell chart width
+	DefaultChartWidth = 60
+	// DefaultChartColor default in cell chart color
+	DefaultChartColor = "#5B9BD5"
+	// DefaultCodeBgColor default background color of inline code
+	DefaultCodeBgColor = "#EEEEEE"
+	// DefaultLinkColor default color of links
+	DefaultLinkColor = "#0366D6"
 )
 
 // ImageType image type for writer
@@ -26,6 +36,10 @@ const (
 	JPEG
 	// PNG png image
 	PNG
+	// SVG svg vector image
+	SVG
+	// PDF pdf document
+	PDF
 )
 
 // Align Alignment
@@ -40,6 +54,10 @@ const (
 	RIGHT
 	// CENTER align center
 	CENTER
+	// DECIMAL align numbers on decimal separator, values of a column line up and are flush right
+	DECIMAL
+	// JUSTIFY stretch wrapped lines except the last line of paragraphs to cell width
+	JUSTIFY
 )
 
 // VAlign vertical alignment
@@ -55,3 +73,27 @@ const (
 	// MIDDLE vertical align bottom
 	MIDDLE
 )
+
+// TextFormat cell text format
+type TextFormat int
+
+const (
+	// UnknownTextFormat unknown text format, cells use table text format which defaults to InlineTags
+	UnknownTextFormat TextFormat = iota
+	// InlineTags text with inline markup tags like <b>bold</b>
+	InlineTags
+	// Plain plain text without formatting
+	Plain
+	// Markdown text with markdown inline formatting like **bold**
+	Markdown
+)
+
+// StylePrecedence precedence between column and row styles
+type StylePrecedence int
+
+const (
+	// ColumnOverRow column style overrides row style, cells inherit cell → column → row → table
+	ColumnOverRow StylePrecedence = iota
+	// RowOverColumn row style overrides column style, cells inherit cell → row → column → table
+	RowOverColumn
+)
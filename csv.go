@@ -0,0 +1,261 @@
+package tableimage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// CSVQuoting quote handling of csv fields
+type CSVQuoting int
+
+const (
+	// StrictQuotes RFC 4180 quoted fields, malformed quotes are errors
+	StrictQuotes CSVQuoting = iota
+	// LazyQuotes quotes may appear in unquoted fields and non-doubled quotes in quoted fields
+	LazyQuotes
+	// NoQuotes quotes are plain characters, fields are split on delimiter only
+	NoQuotes
+)
+
+// CSVHeader header row handling of csv loader
+type CSVHeader int
+
+const (
+	// DetectHeader first row is header if it looks different from the rows below
+	DetectHeader CSVHeader = iota
+	// FirstRowHeader first row is always header
+	FirstRowHeader
+	// NoHeader all rows are data rows
+	NoHeader
+)
+
+// CSVOption csv loader option interface
+type CSVOption interface {
+	apply(*csvLoader)
+}
+
+type csvOptionFunc func(*csvLoader)
+
+func (fn csvOptionFunc) apply(l *csvLoader) {
+	fn(l)
+}
+
+// WithCSVDelimiter set field delimiter, defaults to ','
+func WithCSVDelimiter(delimiter rune) CSVOption {
+	return csvOptionFunc(func(l *csvLoader) {
+		l.delimiter = delimiter
+	})
+}
+
+// WithCSVQuoting set quote handling, defaults to StrictQuotes
+func WithCSVQuoting(quoting CSVQuoting) CSVOption {
+	return csvOptionFunc(func(l *csvLoader) {
+		l.quoting = quoting
+	})
+}
+
+// WithCSVHeader set header row handling, defaults to DetectHeader
+func WithCSVHeader(header CSVHeader) CSVOption {
+	return csvOptionFunc(func(l *csvLoader) {
+		l.header = header
+	})
+}
+
+// WithCSVDecoder set decoder converting input encoding to utf-8,
+// for example func(r io.Reader) io.Reader { return transform.NewReader(r, charmap.Windows1252.NewDecoder()) }
+func WithCSVDecoder(decoder func(io.Reader) io.Reader) CSVOption {
+	return csvOptionFunc(func(l *csvLoader) {
+		l.decoder = decoder
+	})
+}
+
+// WithCSVNumericAlign set alignment of numeric columns, defaults to RIGHT, UnknownAlign disables numeric columns detection
+func WithCSVNumericAlign(align Align) CSVOption {
+	return csvOptionFunc(func(l *csvLoader) {
+		l.numericAlign = align
+	})
+}
+
+type csvLoader struct {
+	delimiter    rune
+	quoting      CSVQuoting
+	header       CSVHeader
+	decoder      func(io.Reader) io.Reader
+	numericAlign Align
+}
+
+// ReadCSV read csv records into rows, cells are plain text, rows are padded to the same columns count
+func ReadCSV(r io.Reader, options ...CSVOption) ([]Row, error) {
+	l := &csvLoader{
+		delimiter:    ',',
+		numericAlign: RIGHT,
+	}
+	for _, opt := range options {
+		opt.apply(l)
+	}
+	return l.load(r)
+}
+
+// ReadTSV read tab separated values into rows, quotes are handled lazily
+func ReadTSV(r io.Reader, options ...CSVOption) ([]Row, error) {
+	options = append([]CSVOption{WithCSVDelimiter('\t'), WithCSVQuoting(LazyQuotes)}, options...)
+	return ReadCSV(r, options...)
+}
+
+// Latin1Decoder decode ISO-8859-1 input to utf-8, could be used with WithCSVDecoder
+func Latin1Decoder(r io.Reader) io.Reader {
+	return &latin1Reader{r: r}
+}
+
+type latin1Reader struct {
+	r   io.Reader
+	buf []byte
+	// err error returned by r with the last read, kept until buf is drained
+	err error
+}
+
+func (lr *latin1Reader) Read(p []byte) (int, error) {
+	if len(lr.buf) == 0 && lr.err == nil {
+		raw := make([]byte, (len(p)+1)/2)
+		n, err := lr.r.Read(raw)
+		var encoded [utf8.UTFMax]byte
+		for _, b := range raw[:n] {
+			size := utf8.EncodeRune(encoded[:], rune(b))
+			lr.buf = append(lr.buf, encoded[:size]...)
+		}
+		lr.err = err
+	}
+	n := copy(p, lr.buf)
+	lr.buf = lr.buf[n:]
+	if len(lr.buf) == 0 {
+		return n, lr.err
+	}
+	return n, nil
+}
+
+func (l *csvLoader) load(r io.Reader) ([]Row, error) {
+	if l.decoder != nil {
+		r = l.decoder(r)
+	}
+	records, err := l.records(r)
+	if err != nil {
+		return nil, err
+	}
+	var columns int
+	for _, record := range records {
+		if len(record) > columns {
+			columns = len(record)
+		}
+	}
+	for idx, record := range records {
+		for len(record) < columns {
+			record = append(record, "")
+		}
+		records[idx] = record
+	}
+	hasHeader := l.hasHeader(records, columns)
+	body := records
+	if hasHeader {
+		body = records[1:]
+	}
+	numeric := make([]bool, columns)
+	if l.numericAlign != UnknownAlign {
+		for col := range numeric {
+			numeric[col] = numericColumn(body, col)
+		}
+	}
+	rows := make([]Row, 0, len(records))
+	for idx, record := range records {
+		cells := make([]Cell, 0, columns)
+		for col, value := range record {
+			cell := Cell{
+				Text:       value,
+				TextFormat: Plain,
+			}
+			if numeric[col] {
+				cell.Style = &Style{Align: l.numericAlign}
+			}
+			cells = append(cells, cell)
+		}
+		rows = append(rows, Row{
+			Cells:  cells,
+			Header: hasHeader && idx == 0,
+		})
+	}
+	return rows, nil
+}
+
+// records read csv records, utf-8 byte order mark is stripped
+func (l *csvLoader) records(r io.Reader) ([][]string, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		br.Discard(3)
+	}
+	if l.quoting == NoQuotes {
+		var records [][]string
+		scanner := bufio.NewScanner(br)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimRight(scanner.Text(), "\r")
+			if line == "" {
+				continue
+			}
+			records = append(records, strings.Split(line, string(l.delimiter)))
+		}
+		return records, scanner.Err()
+	}
+	reader := csv.NewReader(br)
+	reader.Comma = l.delimiter
+	reader.LazyQuotes = l.quoting == LazyQuotes
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// hasHeader check if first record is header, detected when a numeric column has a non numeric first value,
+// or when first record values are all non empty, unique and non numeric
+func (l *csvLoader) hasHeader(records [][]string, columns int) bool {
+	switch l.header {
+	case FirstRowHeader:
+		return len(records) > 0
+	case NoHeader:
+		return false
+	}
+	if len(records) < 2 {
+		return false
+	}
+	first := records[0]
+	seen := make(map[string]bool, columns)
+	distinct := true
+	for col, value := range first {
+		value = strings.TrimSpace(value)
+		_, isNumber := parseNumber(value)
+		if value != "" && !isNumber && numericColumn(records[1:], col) {
+			return true
+		}
+		if value == "" || isNumber || seen[value] {
+			distinct = false
+		}
+		seen[value] = true
+	}
+	return distinct
+}
+
+// numericColumn check if all non empty values of column are numbers, at least one value is required
+func numericColumn(records [][]string, col int) bool {
+	var found bool
+	for _, record := range records {
+		value := strings.TrimSpace(record[col])
+		if value == "" {
+			continue
+		}
+		if _, ok := parseNumber(value); !ok {
+			return false
+		}
+		found = true
+	}
+	return found
+}
@@ -0,0 +1,213 @@
+package tableimage
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// errAfterReader returns data together with err on the last read, later reads return io.EOF
+type errAfterReader struct {
+	data []byte
+	err  error
+}
+
+func (r *errAfterReader) Read(p []byte) (int, error) {
+	if r.err == nil {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	if len(r.data) == 0 {
+		err := r.err
+		r.err = nil
+		return n, err
+	}
+	return n, nil
+}
+
+func TestLatin1Decoder(t *testing.T) {
+	latin1 := []byte("caf\xe9 \xfc\xdf \xa3")
+	want := "café üß £"
+	readers := map[string]io.Reader{
+		"plain":        bytes.NewReader(latin1),
+		"one byte":     iotest.OneByteReader(bytes.NewReader(latin1)),
+		"data and eof": iotest.DataErrReader(bytes.NewReader(latin1)),
+		"half":         iotest.HalfReader(bytes.NewReader(latin1)),
+	}
+	for name, r := range readers {
+		got, err := ioutil.ReadAll(Latin1Decoder(r))
+		if err != nil || string(got) != want {
+			t.Errorf("%s: decoded %q, %v, want %q", name, got, err, want)
+		}
+	}
+	errRead := errors.New("read failed")
+	got, err := ioutil.ReadAll(Latin1Decoder(&errAfterReader{data: latin1, err: errRead}))
+	if err != errRead || string(got) != want {
+		t.Errorf("decoded %q, %v, want %q, %v", got, err, want, errRead)
+	}
+	if err := iotest.TestReader(Latin1Decoder(strings.NewReader("ascii only")), []byte("ascii only")); err != nil {
+		t.Error(err)
+	}
+}
+
+// rowAligns alignment of cells set by the loader, UnknownAlign for cells without style
+func rowAligns(row Row) []Align {
+	aligns := make([]Align, len(row.Cells))
+	for idx, cell := range row.Cells {
+		if cell.Style != nil {
+			aligns[idx] = cell.Style.Align
+		}
+	}
+	return aligns
+}
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		options []CSVOption
+		texts   [][]string
+		header  bool
+		aligns  []Align
+	}{
+		{
+			name:   "detected header with numeric column",
+			s:      "name,qty\napple,3\npear,12\n",
+			texts:  [][]string{{"name", "qty"}, {"apple", "3"}, {"pear", "12"}},
+			header: true,
+			aligns: []Align{UnknownAlign, RIGHT},
+		},
+		{
+			name:   "detected header with distinct labels",
+			s:      "first,last\nada,lovelace\nalan,turing\n",
+			texts:  [][]string{{"first", "last"}, {"ada", "lovelace"}, {"alan", "turing"}},
+			header: true,
+			aligns: []Align{UnknownAlign, UnknownAlign},
+		},
+		{
+			name:   "no header detected",
+			s:      "1,2\n3,4\n",
+			texts:  [][]string{{"1", "2"}, {"3", "4"}},
+			aligns: []Align{RIGHT, RIGHT},
+		},
+		{
+			name:   "repeated first row values are data",
+			s:      "a,a\nb,c\n",
+			texts:  [][]string{{"a", "a"}, {"b", "c"}},
+			aligns: []Align{UnknownAlign, UnknownAlign},
+		},
+		{
+			name:    "forced header",
+			s:       "1,2\n3,4\n",
+			options: []CSVOption{WithCSVHeader(FirstRowHeader)},
+			texts:   [][]string{{"1", "2"}, {"3", "4"}},
+			header:  true,
+			aligns:  []Align{RIGHT, RIGHT},
+		},
+		{
+			name:    "no header",
+			s:       "name,qty\napple,3\n",
+			options: []CSVOption{WithCSVHeader(NoHeader)},
+			texts:   [][]string{{"name", "qty"}, {"apple", "3"}},
+			aligns:  []Align{UnknownAlign, UnknownAlign},
+		},
+		{
+			name:   "quoted fields and bom",
+			s:      "\xef\xbb\xbfname,note\n\"a, b\",\"say \"\"hi\"\"\"\n",
+			texts:  [][]string{{"name", "note"}, {"a, b", `say "hi"`}},
+			header: true,
+			aligns: []Align{UnknownAlign, UnknownAlign},
+		},
+		{
+			name:   "ragged rows are padded",
+			s:      "a,b,c\nx\ny,z\n",
+			texts:  [][]string{{"a", "b", "c"}, {"x", "", ""}, {"y", "z", ""}},
+			header: true,
+			aligns: []Align{UnknownAlign, UnknownAlign, UnknownAlign},
+		},
+		{
+			name:    "semicolon delimiter and numeric align",
+			s:       "item;price\ntea;1.5\n",
+			options: []CSVOption{WithCSVDelimiter(';'), WithCSVNumericAlign(CENTER)},
+			texts:   [][]string{{"item", "price"}, {"tea", "1.5"}},
+			header:  true,
+			aligns:  []Align{UnknownAlign, CENTER},
+		},
+		{
+			name:    "numeric detection disabled",
+			s:       "item,price\ntea,1.5\n",
+			options: []CSVOption{WithCSVNumericAlign(UnknownAlign)},
+			texts:   [][]string{{"item", "price"}, {"tea", "1.5"}},
+			header:  true,
+			aligns:  []Align{UnknownAlign, UnknownAlign},
+		},
+		{
+			name:    "no quotes",
+			s:       "a,b\r\n\"x,y\"\r\n\r\n",
+			options: []CSVOption{WithCSVQuoting(NoQuotes)},
+			texts:   [][]string{{"a", "b"}, {`"x`, `y"`}},
+			header:  true,
+			aligns:  []Align{UnknownAlign, UnknownAlign},
+		},
+		{
+			name:    "latin1",
+			s:       "caf\xe9,na\xefve\n",
+			options: []CSVOption{WithCSVDecoder(Latin1Decoder)},
+			texts:   [][]string{{"café", "naïve"}},
+			aligns:  []Align{UnknownAlign, UnknownAlign},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ReadCSV(strings.NewReader(tt.s), tt.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tableTexts(rows); !reflect.DeepEqual(got, tt.texts) {
+				t.Fatalf("rows = %q, want %q", got, tt.texts)
+			}
+			for idx, row := range rows {
+				if row.Header != (tt.header && idx == 0) {
+					t.Errorf("row %d header = %v", idx, row.Header)
+				}
+				for _, cell := range row.Cells {
+					if cell.TextFormat != Plain {
+						t.Errorf("row %d cell %q text format = %v, want Plain", idx, cell.Text, cell.TextFormat)
+					}
+				}
+			}
+			if got := rowAligns(rows[len(rows)-1]); !reflect.DeepEqual(got, tt.aligns) {
+				t.Errorf("aligns = %v, want %v", got, tt.aligns)
+			}
+		})
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	if _, err := ReadCSV(strings.NewReader("a,\"b\n")); err == nil {
+		t.Error("unterminated quote got no error")
+	}
+	if _, err := ReadCSV(strings.NewReader("a,b\"c\"\n")); err == nil {
+		t.Error("bare quote got no error with StrictQuotes")
+	}
+	if _, err := ReadCSV(strings.NewReader("a,b\"c\"\n"), WithCSVQuoting(LazyQuotes)); err != nil {
+		t.Errorf("bare quote with LazyQuotes error %v", err)
+	}
+}
+
+func TestReadTSV(t *testing.T) {
+	rows, err := ReadTSV(strings.NewReader("name\tsize\nsay \"hi\"\t10\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"name", "size"}, {`say "hi"`, "10"}}
+	if got := tableTexts(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package tableimage
+
+import (
+	"strings"
+	"unicode"
+)
+
+// decimalSeparator decimal separator of cell value, from value format locale, "." by default
+func (c Cell) decimalSeparator() string {
+	if c.Format != nil {
+		return numberLocale(c.Format.Locale).Decimal
+	}
+	return "."
+}
+
+// decimalLines wrapped lines of a decimal aligned cell
+func (c Cell) decimalLines() []Word {
+	if c.Style == nil || c.Style.Font == nil || c.Style.Align != DECIMAL {
+		return nil
+	}
+	lines, _ := c.Wrap(c.textOffset())
+	return lines
+}
+
+// decimalWidths widest parts before and after decimal separator of cell lines, lines without digits are ignored
+func (c Cell) decimalWidths(lines []Word) (int, int) {
+	var (
+		before int
+		after  int
+		sep    = c.decimalSeparator()
+		faces  = make(faceCache)
+	)
+	for _, line := range lines {
+		b, a, ok := decimalSplit(line, sep, faces)
+		if !ok {
+			continue
+		}
+		if b > before {
+			before = b
+		}
+		if a > after {
+			after = a
+		}
+	}
+	return before, after
+}
+
+// decimalSplit widths of line before and after its decimal separator, numbers without separator split after
+// their last digit so that suffixes like "%" stay right of the separator, false if line has no digits
+func decimalSplit(line Word, sep string, faces faceCache) (int, int, bool) {
+	var b strings.Builder
+	for _, txt := range line {
+		b.WriteString(txt.Value)
+	}
+	text := b.String()
+	first := strings.IndexFunc(text, unicode.IsDigit)
+	if first < 0 {
+		return 0, 0, false
+	}
+	split := -1
+	if sep != "" {
+		if idx := strings.Index(text[first:], sep); idx >= 0 {
+			split = first + idx
+		}
+	}
+	if split < 0 {
+		last := strings.LastIndexFunc(text, unicode.IsDigit)
+		split = last + len(string([]rune(text[last:])[0]))
+	}
+	var before, pos int
+	for _, txt := range line {
+		end := pos + len(txt.Value)
+		if end <= split {
+			before += txt.Width
+		} else if pos < split {
+			before += txt.Padding + int(stringWidth(txt.Value[:split-pos], faces.face(txt.Font)))
+		}
+		pos = end
+	}
+	return before, line.Width() - before, true
+}
+
+// alignDecimals share widest integer and fraction parts among decimal aligned cells of each column,
+// spanning cells keep their own widths, cells rewrapped to new widths need their widths aligned again
+func alignDecimals(rows []Row, cols [][]int) {
+	type widths struct {
+		before int
+		after  int
+	}
+	columns := make(map[int]widths)
+	for rowIdx, row := range rows {
+		for cellIdx, cell := range row.Cells {
+			if cell.Span().X > 1 {
+				continue
+			}
+			lines := cell.decimalLines()
+			if lines == nil {
+				continue
+			}
+			before, after := cell.decimalWidths(lines)
+			colIdx := cols[rowIdx][cellIdx]
+			w := columns[colIdx]
+			if before > w.before {
+				w.before = before
+			}
+			if after > w.after {
+				w.after = after
+			}
+			columns[colIdx] = w
+		}
+	}
+	for rowIdx, row := range rows {
+		for cellIdx, cell := range row.Cells {
+			if cell.Span().X > 1 || cell.Style == nil || cell.Style.Align != DECIMAL {
+				continue
+			}
+			w := columns[cols[rowIdx][cellIdx]]
+			row.Cells[cellIdx].decimalBefore = w.before
+			row.Cells[cellIdx].decimalAfter = w.after
+		}
+	}
+}
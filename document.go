@@ -0,0 +1,219 @@
+package tableimage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image"
+	"io"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document declarative table document with table options, caption, footer and rows, could be loaded from JSON or YAML
+type Document struct {
+	// Style table style, missing settings fall back to DefaultStyle
+	Style *Style `json:"style,omitempty"`
+	// HeaderStyle header rows style, inherits table style
+	HeaderStyle *Style `json:"header_style,omitempty"`
+	// FontFolder folder to load fonts in font data from
+	FontFolder string `json:"font_folder,omitempty"`
+	// Columns column width and style specifications
+	Columns []ColumnSpec `json:"columns,omitempty"`
+	// ColumnPrecedence precedence between column and row styles
+	ColumnPrecedence StylePrecedence `json:"column_precedence,omitempty"`
+	// Width exact table width
+	Width int `json:"width,omitempty"`
+	// HeaderRows rows count repeated on every page
+	HeaderRows int `json:"header_rows,omitempty"`
+	// PageNumber page number format like "page %d of %d"
+	PageNumber string `json:"page_number,omitempty"`
+	// TextFormat default text format of cells
+	TextFormat TextFormat `json:"text_format,omitempty"`
+	// StrictMarkup fail on malformed inline markup
+	StrictMarkup bool `json:"strict_markup,omitempty"`
+	// Stripes zebra striping rules
+	Stripes []StripeRule `json:"stripes,omitempty"`
+	// Rules numeric conditional formatting rules
+	Rules []NumericRule `json:"rules,omitempty"`
+	// ImageType output image type, PNG by default
+	ImageType ImageType `json:"image_type,omitempty"`
+	// Caption table caption
+	Caption *Cell `json:"caption,omitempty"`
+	// Footer table footer
+	Footer *Cell `json:"footer,omitempty"`
+	// Rows table rows
+	Rows []Row `json:"rows,omitempty"`
+}
+
+// ParseDocument parse JSON or YAML document
+func ParseDocument(data []byte) (*Document, error) {
+	doc := new(Document)
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := unmarshalJSON(data, doc); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// ReadDocument read JSON or YAML document from reader
+func ReadDocument(r io.Reader) (*Document, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDocument(data)
+}
+
+// UnmarshalYAML implement yaml.Unmarshaler, YAML documents share keys and shorthands with JSON documents
+func (d *Document) UnmarshalYAML(value *yaml.Node) error {
+	var v interface{}
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	type document Document
+	return unmarshalJSON(data, (*document)(d))
+}
+
+// unmarshalJSON decode JSON keeping numbers of cell values as json.Number, so that big integers keep their digits
+func unmarshalJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid data after top-level value")
+	}
+	return nil
+}
+
+// MarshalYAML implement yaml.Marshaler
+func (d Document) MarshalYAML() (interface{}, error) {
+	type document Document
+	data, err := json.Marshal(document(d))
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	clearYAMLStyle(&node)
+	return node.Content[0], nil
+}
+
+// clearYAMLStyle reset json flow and quoting styles so that nodes are written in block style
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
+// Options table image options of document
+func (d Document) Options() []Option {
+	var options []Option
+	if d.FontFolder != "" {
+		options = append(options, WithFontFolder(d.FontFolder))
+	}
+	if d.Style != nil {
+		options = append(options, withDocumentStyle(d.Style))
+	}
+	if d.HeaderStyle != nil {
+		options = append(options, WithHeaderStyle(d.HeaderStyle))
+	}
+	if len(d.Columns) > 0 {
+		options = append(options, WithColumns(d.Columns...))
+	}
+	options = append(options, WithColumnStylePrecedence(d.ColumnPrecedence))
+	if d.Width > 0 {
+		options = append(options, WithTableWidth(d.Width))
+	}
+	if d.HeaderRows > 0 {
+		options = append(options, WithHeaderRows(d.HeaderRows))
+	}
+	if d.PageNumber != "" {
+		options = append(options, WithPageNumber(d.PageNumber))
+	}
+	if d.TextFormat != UnknownTextFormat {
+		options = append(options, WithTextFormat(d.TextFormat))
+	}
+	if d.StrictMarkup {
+		options = append(options, WithStrictMarkup())
+	}
+	for _, stripe := range d.Stripes {
+		options = append(options, WithRules(stripe))
+	}
+	for _, rule := range d.Rules {
+		options = append(options, WithRules(rule))
+	}
+	return options
+}
+
+// TableImage create TableImage with document options, extra options are applied after them
+func (d Document) TableImage(options ...Option) (*TableImage, error) {
+	return New(append(d.Options(), options...)...)
+}
+
+// Draw document table into image
+func (d Document) Draw(options ...Option) (*image.RGBA, error) {
+	ti, err := d.TableImage(options...)
+	if err != nil {
+		return nil, err
+	}
+	return ti.Draw(d.Rows, d.Caption, d.Footer)
+}
+
+// Write document table to writer in document image type
+func (d Document) Write(w io.Writer, options ...Option) error {
+	ti, err := d.TableImage(options...)
+	if err != nil {
+		return err
+	}
+	imageType := d.ImageType
+	if imageType == 0 {
+		imageType = PNG
+	}
+	return ti.Write(w, d.Rows, d.Caption, d.Footer, imageType)
+}
+
+// withDocumentStyle merge document style into table style, settings missing in document style are kept,
+// fonts are loaded by New
+func withDocumentStyle(style *Style) Option {
+	return optionFunc(func(ti *TableImage) {
+		s := *style
+		font := s.Font
+		s.Font = nil
+		if ti.style != nil {
+			s.Inherit(ti.style, nil)
+		}
+		if font != nil {
+			f := *font
+			if parent := s.Font; parent != nil {
+				if f.Size < 1e-15 {
+					f.Size = parent.Size
+				}
+				if f.Data == nil {
+					f.Data = parent.Data
+					f.Font = parent.Font
+				}
+				if f.DPI <= 0 {
+					f.DPI = parent.DPI
+				}
+			}
+			s.Font = &f
+		}
+		ti.style = &s
+	})
+}
@@ -0,0 +1,48 @@
+package tableimage
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDocumentNumbers(t *testing.T) {
+	docs := map[string]string{
+		"json": `{"rows": [{"cells": [
+			{"value": 12345678901234567890, "format": {"thousands": true}},
+			{"value": -42},
+			{"value": 1.5},
+			{"value": 1e21}
+		]}]}`,
+		"yaml": `rows:
+  - cells:
+      - value: 12345678901234567890
+        format:
+          thousands: true
+      - value: -42
+      - value: 1.5
+      - value: 1e21
+`,
+	}
+	want := []string{"12,345,678,901,234,567,890", "-42", "1.5", "1000000000000000000000"}
+	for name, s := range docs {
+		doc, err := ParseDocument([]byte(s))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		cells := doc.Rows[0].Cells
+		if _, ok := cells[0].Value.(json.Number); !ok {
+			t.Errorf("%s: value type %T, want json.Number", name, cells[0].Value)
+		}
+		for idx, cell := range cells {
+			if got := cell.renderValue().Text; got != want[idx] {
+				t.Errorf("%s: cell %d = %q, want %q", name, idx, got, want[idx])
+			}
+		}
+	}
+}
+
+func TestParseDocumentTrailingData(t *testing.T) {
+	if _, err := ParseDocument([]byte(`{"rows": []} {}`)); err == nil {
+		t.Error("trailing data got no error")
+	}
+}
@@ -2,21 +2,60 @@ package tableimage
 
 import (
 	"image"
+	"image/draw"
+	"math"
 
 	"github.com/golang/freetype"
+	"github.com/llgcode/draw2d"
 	"github.com/llgcode/draw2d/draw2dimg"
 	"github.com/llgcode/draw2d/draw2dkit"
 )
 
-func drawText(img *image.RGBA, bounds image.Rectangle, txt *Text, font *Font) {
-	if txt.BgColor != "" {
-		drawRect(img, bounds, "", txt.BgColor, 1)
+// RasterRenderer a Renderer draws into an *image.RGBA with draw2dimg
+type RasterRenderer struct {
+	img   *image.RGBA
+	clips []image.Rectangle
+}
+
+// NewRasterRenderer create RasterRenderer for img
+func NewRasterRenderer(img *image.RGBA) *RasterRenderer {
+	return &RasterRenderer{img: img}
+}
+
+// Image get target image
+func (r *RasterRenderer) Image() *image.RGBA {
+	return r.img
+}
+
+// FillRect implement Renderer
+func (r *RasterRenderer) FillRect(bounds image.Rectangle, color string) {
+	if color == "" {
+		return
+	}
+	r.draw(func(gc draw2d.GraphicContext) {
+		gc.SetFillColor(nrgbaFromHex(color))
+		draw2dkit.Rectangle(gc, float64(bounds.Min.X), float64(bounds.Min.Y), float64(bounds.Max.X), float64(bounds.Max.Y))
+		gc.Fill()
+	})
+}
+
+// StrokeLine implement Renderer
+func (r *RasterRenderer) StrokeLine(from image.Point, to image.Point, color string, width int) {
+	if width == 0 {
+		return
 	}
-	point := bounds.Min.Add(image.Pt(txt.Padding, 0))
-	drawString(img, point, txt.Value, txt.Color, font)
+	r.draw(func(gc draw2d.GraphicContext) {
+		gc.SetStrokeColor(nrgbaFromHex(color))
+		gc.SetLineWidth(float64(width))
+		gc.MoveTo(float64(from.X), float64(from.Y))
+		gc.LineTo(float64(to.X), float64(to.Y))
+		gc.Stroke()
+	})
 }
 
-func drawString(img *image.RGBA, point image.Point, label string, color string, font *Font) {
+// DrawGlyphRun implement Renderer
+func (r *RasterRenderer) DrawGlyphRun(pt image.Point, run GlyphRun) {
+	font := run.Font
 	if font == nil || font.Font == nil {
 		return
 	}
@@ -29,36 +68,92 @@ func drawString(img *image.RGBA, point image.Point, label string, color string,
 	fontCtx.SetDPI(dpi)
 	fontCtx.SetFont(font.Font)
 	fontCtx.SetFontSize(fontSize)
-	fontCtx.SetClip(img.Bounds())
-	fontCtx.SetDst(img)
-	fontCtx.SetSrc(image.NewUniform(ColorFromHex(color)))
-	pt := freetype.Pt(point.X, point.Y+int(fontCtx.PointToFixed(fontSize)>>6))
-	fontCtx.DrawString(label, pt)
+	fontCtx.SetClip(r.clip())
+	fontCtx.SetDst(r.img)
+	fontCtx.SetSrc(image.NewUniform(nrgbaFromHex(run.Color)))
+	baseline := freetype.Pt(pt.X, pt.Y+int(fontCtx.PointToFixed(fontSize)>>6))
+	fontCtx.DrawString(run.Text, baseline)
 }
 
-func drawRect(img *image.RGBA, bounds image.Rectangle, borderColor string, bgColor string, strokeWidth float64) {
-	gc := draw2dimg.NewGraphicContext(img)
-	gc.SetStrokeColor(ColorFromHex(borderColor))
-	if bgColor != "" {
-		gc.SetFillColor(ColorFromHex(bgColor))
+// DrawImage implement Renderer
+func (r *RasterRenderer) DrawImage(img *Image, pt image.Point) {
+	if img == nil || img.Data == nil {
+		return
 	}
-	gc.SetLineWidth(strokeWidth)
-	draw2dkit.Rectangle(gc, float64(bounds.Min.X), float64(bounds.Min.Y), float64(bounds.Max.X), float64(bounds.Max.Y))
-	gc.FillStroke()
-	if bgColor != "" {
-		gc.Fill()
+	scale := img.Scale()
+	scaledImage := scaleImage(img.Data, scale)
+	r.draw(func(gc draw2d.GraphicContext) {
+		gc.Translate(float64(pt.X), float64(pt.Y))
+		gc.DrawImage(scaledImage)
+	})
+}
+
+// PushClip implement Renderer
+func (r *RasterRenderer) PushClip(bounds image.Rectangle) {
+	r.clips = append(r.clips, bounds.Intersect(r.clip()))
+}
+
+// PopClip implement Renderer
+func (r *RasterRenderer) PopClip() {
+	if len(r.clips) > 0 {
+		r.clips = r.clips[:len(r.clips)-1]
 	}
 }
 
-func drawImage(dst *image.RGBA, img *Image, pt image.Point) {
-	if img == nil || img.Data == nil {
+func (r *RasterRenderer) clip() image.Rectangle {
+	if len(r.clips) == 0 {
+		return r.img.Bounds()
+	}
+	return r.clips[len(r.clips)-1]
+}
+
+// draw run fn with a graphic context, when clipped fn draws into a layer of clip size composed onto the image
+func (r *RasterRenderer) draw(fn func(gc draw2d.GraphicContext)) {
+	if len(r.clips) == 0 {
+		fn(draw2dimg.NewGraphicContext(r.img))
 		return
 	}
-	scale := img.Scale()
-	scaledImage := scaleImage(img.Data, scale)
-	gc := draw2dimg.NewGraphicContext(dst)
-	gc.Translate(float64(pt.X), float64(pt.Y))
-	gc.DrawImage(scaledImage)
+	clip := r.clip()
+	if clip.Empty() {
+		return
+	}
+	layer := image.NewRGBA(image.Rect(0, 0, clip.Dx(), clip.Dy()))
+	gc := draw2dimg.NewGraphicContext(layer)
+	gc.Translate(-float64(clip.Min.X), -float64(clip.Min.Y))
+	fn(gc)
+	draw.Draw(r.img, clip, layer, image.ZP, draw.Over)
+}
+
+// drawText draw text run in line bounds, the run uses its own font if set and sits on the line baseline,
+// which is the offset from top of bounds
+func drawText(r Renderer, bounds image.Rectangle, txt *Text, font *Font, baseline int) {
+	if txt.BgColor != "" {
+		r.FillRect(bounds, txt.BgColor)
+	}
+	if txt.Font != nil {
+		font = txt.Font
+	}
+	width := txt.Width - txt.Padding*2
+	point := bounds.Min.Add(image.Pt(txt.Padding, baseline-int(font.Size)))
+	r.DrawGlyphRun(point, GlyphRun{
+		Text:  txt.Value,
+		Width: width,
+		Color: txt.Color,
+		Font:  font,
+	})
+	if !txt.Underline && !txt.Strike {
+		return
+	}
+	lineWidth := int(math.Max(1, math.Round(font.Size/16)))
+	y := bounds.Min.Y + baseline
+	if txt.Underline {
+		underline := y + lineWidth + 1
+		r.StrokeLine(image.Pt(point.X, underline), image.Pt(point.X+width, underline), txt.Color, lineWidth)
+	}
+	if txt.Strike {
+		strike := y - int(font.Size*0.3)
+		r.StrokeLine(image.Pt(point.X, strike), image.Pt(point.X+width, strike), txt.Color, lineWidth)
+	}
 }
 
 func scaleImage(img image.Image, scale float64) *image.RGBA {
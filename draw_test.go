@@ -0,0 +1,36 @@
+package tableimage
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestRasterRendererAlpha(t *testing.T) {
+	tests := []struct {
+		color string
+		want  color.RGBA
+	}{
+		{"#FF0000", color.RGBA{255, 0, 0, 255}},
+		{"rgba(255, 0, 0, 0.5)", color.RGBA{255, 128, 128, 255}},
+		{"#0000FF80", color.RGBA{127, 127, 255, 255}},
+		{"#00000000", color.RGBA{255, 255, 255, 255}},
+	}
+	for _, tt := range tests {
+		img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+		draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		NewRasterRenderer(img).FillRect(img.Bounds(), tt.color)
+		if got := img.RGBAAt(2, 2); !closeRGBA(got, tt.want) {
+			t.Errorf("FillRect(%q) on white = %v, want %v", tt.color, got, tt.want)
+		}
+	}
+}
+
+// closeRGBA check if colors differ by at most 1 per channel
+func closeRGBA(a color.RGBA, b color.RGBA) bool {
+	diff := func(x, y uint8) bool {
+		return x-y <= 1 || y-x <= 1
+	}
+	return diff(a.R, b.R) && diff(a.G, b.G) && diff(a.B, b.B) && diff(a.A, b.A)
+}
@@ -0,0 +1,426 @@
+package tableimage
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/llgcode/draw2d"
+)
+
+var (
+	imageTypeNames       = []string{"", "jpeg", "png", "svg", "pdf"}
+	alignNames           = []string{"", "left", "right", "center", "decimal", "justify"}
+	valignNames          = []string{"", "top", "bottom", "middle"}
+	textFormatNames      = []string{"", "inline_tags", "plain", "markdown"}
+	chartTypeNames       = []string{"", "data_bar", "sparkline", "bar_sparkline"}
+	stylePrecedenceNames = []string{"column_over_row", "row_over_column"}
+)
+
+// MarshalText implement encoding.TextMarshaler
+func (t ImageType) MarshalText() ([]byte, error) {
+	return marshalEnum(int(t), imageTypeNames, "image type")
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler, accepts name or number
+func (t *ImageType) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnum(text, imageTypeNames, "image type")
+	*t = ImageType(v)
+	return err
+}
+
+// UnmarshalJSON implement json.Unmarshaler, accepts name or number
+func (t *ImageType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, t)
+}
+
+// ContentType mime type of image type
+func (t ImageType) ContentType() string {
+	switch t {
+	case JPEG:
+		return "image/jpeg"
+	case PNG:
+		return "image/png"
+	case SVG:
+		return "image/svg+xml"
+	case PDF:
+		return "application/pdf"
+	}
+	return "application/octet-stream"
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (a Align) MarshalText() ([]byte, error) {
+	return marshalEnum(int(a), alignNames, "align")
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler, accepts name or number
+func (a *Align) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnum(text, alignNames, "align")
+	*a = Align(v)
+	return err
+}
+
+// UnmarshalJSON implement json.Unmarshaler, accepts name or number
+func (a *Align) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, a)
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (a VAlign) MarshalText() ([]byte, error) {
+	return marshalEnum(int(a), valignNames, "valign")
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler, accepts name or number
+func (a *VAlign) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnum(text, valignNames, "valign")
+	*a = VAlign(v)
+	return err
+}
+
+// UnmarshalJSON implement json.Unmarshaler, accepts name or number
+func (a *VAlign) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, a)
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (f TextFormat) MarshalText() ([]byte, error) {
+	return marshalEnum(int(f), textFormatNames, "text format")
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler, accepts name or number
+func (f *TextFormat) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnum(text, textFormatNames, "text format")
+	*f = TextFormat(v)
+	return err
+}
+
+// UnmarshalJSON implement json.Unmarshaler, accepts name or number
+func (f *TextFormat) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, f)
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (t ChartType) MarshalText() ([]byte, error) {
+	return marshalEnum(int(t), chartTypeNames, "chart type")
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler, accepts name or number
+func (t *ChartType) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnum(text, chartTypeNames, "chart type")
+	*t = ChartType(v)
+	return err
+}
+
+// UnmarshalJSON implement json.Unmarshaler, accepts name or number
+func (t *ChartType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, t)
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (p StylePrecedence) MarshalText() ([]byte, error) {
+	return marshalEnum(int(p), stylePrecedenceNames, "style precedence")
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler, accepts name or number
+func (p *StylePrecedence) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnum(text, stylePrecedenceNames, "style precedence")
+	*p = StylePrecedence(v)
+	return err
+}
+
+// UnmarshalJSON implement json.Unmarshaler, accepts name or number
+func (p *StylePrecedence) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, p)
+}
+
+func marshalEnum(v int, names []string, kind string) ([]byte, error) {
+	if v < 0 || v >= len(names) {
+		return nil, fmt.Errorf("invalid %s %d", kind, v)
+	}
+	return []byte(names[v]), nil
+}
+
+func unmarshalEnum(text []byte, names []string, kind string) (int, error) {
+	name := strings.ToLower(strings.TrimSpace(string(text)))
+	for v, n := range names {
+		if n == name {
+			return v, nil
+		}
+	}
+	if v, err := strconv.Atoi(name); err == nil && v >= 0 && v < len(names) {
+		return v, nil
+	}
+	return 0, fmt.Errorf("invalid %s %q", kind, text)
+}
+
+// unmarshalEnumJSON unmarshal enum from json string or number
+func unmarshalEnumJSON(data []byte, u encoding.TextUnmarshaler) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return u.UnmarshalText([]byte(s))
+	}
+	return u.UnmarshalText(data)
+}
+
+// UnmarshalJSON implement json.Unmarshaler, accepts object, number for all sides
+// or css like shorthand string "top right bottom left", "top right/left bottom", "top/bottom right/left"
+func (p *Padding) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		type padding Padding
+		return json.Unmarshal(data, (*padding)(p))
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch s := v.(type) {
+	case nil:
+		return nil
+	case float64:
+		*p = *NewPadding(int(s))
+		return nil
+	case string:
+		padding, err := ParsePadding(s)
+		if err != nil {
+			return err
+		}
+		*p = *padding
+		return nil
+	}
+	return fmt.Errorf("invalid padding %s", data)
+}
+
+// ParsePadding parse css like padding shorthand, "10", "10 5", "10 5 8" or "10 5 8 4", px units are allowed
+func ParsePadding(s string) (*Padding, error) {
+	fields := strings.Fields(s)
+	values := make([]int, 0, 4)
+	for _, field := range fields {
+		v, err := strconv.Atoi(strings.TrimSuffix(field, "px"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid padding %q", s)
+		}
+		values = append(values, v)
+	}
+	switch len(values) {
+	case 1:
+		return NewPadding(values[0]), nil
+	case 2:
+		return NewPaddingXY(values[1], values[0]), nil
+	case 3:
+		return &Padding{Top: values[0], Right: values[1], Bottom: values[2], Left: values[1]}, nil
+	case 4:
+		return &Padding{Top: values[0], Right: values[1], Bottom: values[2], Left: values[3]}, nil
+	}
+	return nil, fmt.Errorf("invalid padding %q", s)
+}
+
+// UnmarshalJSON implement json.Unmarshaler, accepts object, number width, or shorthand string "1 #CCCCCC"
+func (l *Line) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		type line Line
+		return json.Unmarshal(data, (*line)(l))
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch s := v.(type) {
+	case nil:
+		return nil
+	case float64:
+		*l = Line{
+			Color: DefaultColor,
+			Width: int(s),
+		}
+		return nil
+	case string:
+		line, err := ParseLine(s)
+		if err != nil {
+			return err
+		}
+		*l = line
+		return nil
+	}
+	return fmt.Errorf("invalid line %s", data)
+}
+
+// ParseLine parse line shorthand with width and color in any order like "1 #CCCCCC", "2px rgb(0, 0, 0)" or "none",
+// a line style keyword like solid is ignored
+func ParseLine(s string) (Line, error) {
+	var l Line
+	for _, token := range shorthandTokens(s) {
+		switch token {
+		case "none":
+			return Line{}, nil
+		case "solid":
+			continue
+		}
+		if width, err := strconv.Atoi(strings.TrimSuffix(token, "px")); err == nil {
+			l.Width = width
+			continue
+		}
+		if !isColor(token) {
+			return l, fmt.Errorf("invalid line %q", s)
+		}
+		l.Color = token
+	}
+	if l.Color != "" && l.Width == 0 {
+		l.Width = DefaultBorderWidth
+	}
+	if l.Width > 0 && l.Color == "" {
+		l.Color = DefaultColor
+	}
+	return l, nil
+}
+
+// UnmarshalJSON implement json.Unmarshaler, accepts object, number width or line shorthand string for all sides
+func (b *Border) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		type border Border
+		return json.Unmarshal(data, (*border)(b))
+	}
+	var l Line
+	if err := l.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*b = Border{
+		Top:    l,
+		Right:  l,
+		Bottom: l,
+		Left:   l,
+	}
+	return nil
+}
+
+// shorthandTokens split shorthand by spaces, keeping spaces inside parentheses
+func shorthandTokens(s string) []string {
+	var (
+		tokens []string
+		depth  int
+		start  = -1
+	)
+	for i, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ' ' || r == '\t':
+			if depth == 0 {
+				if start >= 0 {
+					tokens = append(tokens, s[start:i])
+				}
+				start = -1
+				continue
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+var (
+	fontFamilyNames = []string{"sans", "serif", "mono"}
+	fontStyleNames  = []string{"normal", "bold", "italic", "bold_italic"}
+)
+
+// fontJSON json form of Font, font data family and style are names
+type fontJSON struct {
+	Size float64       `json:"size,omitempty"`
+	Data *fontDataJSON `json:"data,omitempty"`
+	DPI  int           `json:"dpi,omitempty"`
+}
+
+type fontDataJSON struct {
+	Name   string         `json:"name,omitempty"`
+	Family fontFamilyJSON `json:"family,omitempty"`
+	Style  fontStyleJSON  `json:"style,omitempty"`
+}
+
+type fontFamilyJSON draw2d.FontFamily
+
+func (f fontFamilyJSON) MarshalText() ([]byte, error) {
+	return marshalEnum(int(f), fontFamilyNames, "font family")
+}
+
+func (f *fontFamilyJSON) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnum(text, fontFamilyNames, "font family")
+	*f = fontFamilyJSON(v)
+	return err
+}
+
+func (f *fontFamilyJSON) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, f)
+}
+
+type fontStyleJSON draw2d.FontStyle
+
+func (s fontStyleJSON) MarshalText() ([]byte, error) {
+	return marshalEnum(int(s), fontStyleNames, "font style")
+}
+
+func (s *fontStyleJSON) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnum(text, fontStyleNames, "font style")
+	*s = fontStyleJSON(v)
+	return err
+}
+
+func (s *fontStyleJSON) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, s)
+}
+
+// MarshalJSON implement json.Marshaler, font data family and style are written as names like "sans" and "bold"
+func (f Font) MarshalJSON() ([]byte, error) {
+	v := fontJSON{
+		Size: f.Size,
+		DPI:  f.DPI,
+	}
+	if f.Data != nil {
+		v.Data = &fontDataJSON{
+			Name:   f.Data.Name,
+			Family: fontFamilyJSON(f.Data.Family),
+			Style:  fontStyleJSON(f.Data.Style),
+		}
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implement json.Unmarshaler, font data family and style accept names or numbers
+func (f *Font) UnmarshalJSON(data []byte) error {
+	var v fontJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	f.Size = v.Size
+	f.DPI = v.DPI
+	if v.Data != nil {
+		if err := CheckFontName(v.Data.Name); err != nil {
+			return err
+		}
+		f.Data = &draw2d.FontData{
+			Name:   v.Data.Name,
+			Family: draw2d.FontFamily(v.Data.Family),
+			Style:  draw2d.FontStyle(v.Data.Style),
+		}
+	}
+	return nil
+}
@@ -69,7 +69,8 @@ func main() {
 
 	rows := []tableimage.Row{
 		{
-			Style: headerStyle,
+			Header: true,
+			Style:  headerStyle,
 			Cells: []tableimage.Cell{
 				{
 					Text: "Id",
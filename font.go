@@ -0,0 +1,123 @@
+package tableimage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"github.com/llgcode/draw2d"
+)
+
+// FontFileCache font cache which keeps truetype font file content, PDF output embeds fonts loaded from it
+type FontFileCache interface {
+	draw2d.FontCache
+	// LoadFile returns truetype font file content of font data
+	LoadFile(draw2d.FontData) ([]byte, error)
+}
+
+// CheckFontName reject font names which could resolve to files outside of font folder,
+// names with path separators or ".." are invalid
+func CheckFontName(name string) error {
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid font name %q", name)
+	}
+	return nil
+}
+
+// fontFileName draw2d font file name of font data with a checked font name
+func fontFileName(data draw2d.FontData) (string, error) {
+	if err := CheckFontName(data.Name); err != nil {
+		return "", err
+	}
+	return draw2d.FontFileName(data), nil
+}
+
+// FolderFontCache thread safe FontFileCache loading fonts from a folder with draw2d font file naming,
+// font names which could resolve outside of the folder are rejected
+type FolderFontCache struct {
+	sync.RWMutex
+	folder string
+	fonts  map[string]*truetype.Font
+	files  map[string][]byte
+}
+
+// NewFolderFontCache create FolderFontCache of font folder
+func NewFolderFontCache(folder string) *FolderFontCache {
+	return &FolderFontCache{
+		folder: folder,
+		fonts:  make(map[string]*truetype.Font),
+		files:  make(map[string][]byte),
+	}
+}
+
+// Load implement draw2d.FontCache, loads font from font folder if it's not cached
+func (c *FolderFontCache) Load(data draw2d.FontData) (*truetype.Font, error) {
+	name, err := fontFileName(data)
+	if err != nil {
+		return nil, err
+	}
+	c.RLock()
+	ft := c.fonts[name]
+	c.RUnlock()
+	if ft != nil {
+		return ft, nil
+	}
+	file, err := c.LoadFile(data)
+	if err != nil {
+		return nil, err
+	}
+	ft, err = truetype.Parse(file)
+	if err != nil {
+		return nil, err
+	}
+	c.Lock()
+	c.fonts[name] = ft
+	c.Unlock()
+	return ft, nil
+}
+
+// Store implement draw2d.FontCache, PDF output reads the file of a stored font from font folder, use StoreFile to keep its content
+func (c *FolderFontCache) Store(data draw2d.FontData, ft *truetype.Font) {
+	c.Lock()
+	c.fonts[draw2d.FontFileName(data)] = ft
+	c.Unlock()
+}
+
+// LoadFile implement FontFileCache, reads font file from font folder if it's not cached
+func (c *FolderFontCache) LoadFile(data draw2d.FontData) ([]byte, error) {
+	name, err := fontFileName(data)
+	if err != nil {
+		return nil, err
+	}
+	c.RLock()
+	file, found := c.files[name]
+	c.RUnlock()
+	if found {
+		return file, nil
+	}
+	file, err = ioutil.ReadFile(filepath.Join(c.folder, name))
+	if err != nil {
+		return nil, err
+	}
+	c.Lock()
+	c.files[name] = file
+	c.Unlock()
+	return file, nil
+}
+
+// StoreFile parse truetype font file content and store both the font and the file
+func (c *FolderFontCache) StoreFile(data draw2d.FontData, file []byte) error {
+	ft, err := truetype.Parse(file)
+	if err != nil {
+		return err
+	}
+	name := draw2d.FontFileName(data)
+	c.Lock()
+	c.fonts[name] = ft
+	c.files[name] = file
+	c.Unlock()
+	return nil
+}
@@ -0,0 +1,191 @@
+package tableimage
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// MarkdownTable table parsed from github flavored markdown pipe table
+type MarkdownTable struct {
+	// Caption text of the heading before the table with only blank lines between them, nil if there's none
+	Caption *Cell
+	// Rows table rows, the first row is the header row
+	Rows []Row
+	// Aligns column alignments from delimiter row, UnknownAlign for columns without alignment markers
+	Aligns []Align
+}
+
+// ParseMarkdownTable parse the first github flavored markdown pipe table from r
+func ParseMarkdownTable(r io.Reader) (*MarkdownTable, error) {
+	tables, err := ParseMarkdownTables(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, errors.New("no markdown table found")
+	}
+	return &tables[0], nil
+}
+
+// ParseMarkdownTables parse all github flavored markdown pipe tables from r,
+// cells use Markdown text format and columns alignment from the delimiter row
+func ParseMarkdownTables(r io.Reader) ([]MarkdownTable, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	var (
+		tables  []MarkdownTable
+		heading string
+		fence   string
+	)
+	for idx := 0; idx < len(lines); idx++ {
+		line := lines[idx]
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			heading = ""
+			continue
+		}
+		if text, ok := markdownHeading(trimmed); ok {
+			heading = text
+			continue
+		}
+		if idx+1 >= len(lines) || !strings.Contains(line, "|") {
+			if trimmed != "" {
+				// a heading followed by other content doesn't caption the next table
+				heading = ""
+			}
+			continue
+		}
+		header := splitMarkdownRow(line)
+		aligns, ok := markdownAligns(lines[idx+1])
+		if !ok || len(aligns) != len(header) {
+			heading = ""
+			continue
+		}
+		table := MarkdownTable{
+			Aligns: aligns,
+			Rows:   []Row{markdownRow(header, aligns, true)},
+		}
+		if heading != "" {
+			table.Caption = &Cell{
+				Text:       heading,
+				TextFormat: Markdown,
+			}
+			heading = ""
+		}
+		idx += 2
+		for ; idx < len(lines); idx++ {
+			line = lines[idx]
+			if strings.TrimSpace(line) == "" || !strings.Contains(line, "|") {
+				break
+			}
+			table.Rows = append(table.Rows, markdownRow(splitMarkdownRow(line), aligns, false))
+		}
+		idx--
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// markdownRow create row from cells text, cells are padded or truncated to columns count
+func markdownRow(texts []string, aligns []Align, header bool) Row {
+	cells := make([]Cell, len(aligns))
+	for idx, align := range aligns {
+		cells[idx].TextFormat = Markdown
+		if idx < len(texts) {
+			cells[idx].Text = texts[idx]
+		}
+		if align != UnknownAlign {
+			cells[idx].Style = &Style{Align: align}
+		}
+	}
+	return Row{
+		Cells:  cells,
+		Header: header,
+	}
+}
+
+// splitMarkdownRow split pipe table row into cells, escaped pipes \| are kept in cell text
+func splitMarkdownRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = line[:len(line)-1]
+	}
+	var (
+		cells []string
+		cell  strings.Builder
+	)
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// markdownAligns parse delimiter row like | :--- | :---: | ---: |
+func markdownAligns(line string) ([]Align, bool) {
+	if !strings.Contains(line, "-") {
+		return nil, false
+	}
+	cells := splitMarkdownRow(line)
+	aligns := make([]Align, 0, len(cells))
+	for _, cell := range cells {
+		left := strings.HasPrefix(cell, ":")
+		right := strings.HasSuffix(cell, ":")
+		dashes := strings.Trim(cell, ":")
+		if dashes == "" || strings.Trim(dashes, "-") != "" {
+			return nil, false
+		}
+		switch {
+		case left && right:
+			aligns = append(aligns, CENTER)
+		case right:
+			aligns = append(aligns, RIGHT)
+		case left:
+			aligns = append(aligns, LEFT)
+		default:
+			aligns = append(aligns, UnknownAlign)
+		}
+	}
+	return aligns, true
+}
+
+// markdownHeading parse atx heading like ## Title ##
+func markdownHeading(line string) (string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level < len(line) && line[level] != ' ' && line[level] != '\t' {
+		return "", false
+	}
+	text := strings.TrimSpace(line[level:])
+	if trimmed := strings.TrimRight(text, "#"); trimmed == "" || strings.HasSuffix(trimmed, " ") {
+		text = strings.TrimSpace(trimmed)
+	}
+	return text, true
+}
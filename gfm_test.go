@@ -0,0 +1,155 @@
+package tableimage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// tableTexts cell texts of rows
+func tableTexts(rows []Row) [][]string {
+	texts := make([][]string, len(rows))
+	for idx, row := range rows {
+		for _, cell := range row.Cells {
+			texts[idx] = append(texts[idx], cell.Text)
+		}
+	}
+	return texts
+}
+
+func TestParseMarkdownTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		texts   [][]string
+		aligns  []Align
+		caption string
+	}{
+		{
+			name:   "simple",
+			s:      "| a | b |\n|---|---|\n| 1 | 2 |\n",
+			texts:  [][]string{{"a", "b"}, {"1", "2"}},
+			aligns: []Align{UnknownAlign, UnknownAlign},
+		},
+		{
+			name:   "aligns",
+			s:      "a | b | c | d\n:--- | :---: | ---: | ---\n1 | 2 | 3 | 4",
+			texts:  [][]string{{"a", "b", "c", "d"}, {"1", "2", "3", "4"}},
+			aligns: []Align{LEFT, CENTER, RIGHT, UnknownAlign},
+		},
+		{
+			name:   "escaped pipe",
+			s:      "| a | b |\n|---|---|\n| x \\| y | z \\|\n",
+			texts:  [][]string{{"a", "b"}, {"x | y", "z |"}},
+			aligns: []Align{UnknownAlign, UnknownAlign},
+		},
+		{
+			name:   "padded and truncated rows",
+			s:      "| a | b |\n|---|---|\n| 1 |\n| 1 | 2 | 3 |\n",
+			texts:  [][]string{{"a", "b"}, {"1", ""}, {"1", "2"}},
+			aligns: []Align{UnknownAlign, UnknownAlign},
+		},
+		{
+			name:    "heading caption",
+			s:       "# Sales ##\n\n\n| a |\n|---|\n| 1 |\n\nafter | table\n",
+			texts:   [][]string{{"a"}, {"1"}},
+			aligns:  []Align{UnknownAlign},
+			caption: "Sales",
+		},
+		{
+			name:   "paragraph after heading",
+			s:      "# Sales\n\ntext\n\n| a |\n|---|\n",
+			texts:  [][]string{{"a"}},
+			aligns: []Align{UnknownAlign},
+		},
+		{
+			name:   "code after heading",
+			s:      "# Sales\n```\ncode\n```\n| a |\n|---|\n",
+			texts:  [][]string{{"a"}},
+			aligns: []Align{UnknownAlign},
+		},
+		{
+			name:   "pipe text after heading",
+			s:      "# Sales\nx | y\n\n| a |\n|---|\n",
+			texts:  [][]string{{"a"}},
+			aligns: []Align{UnknownAlign},
+		},
+		{
+			name:   "crlf",
+			s:      "| a |\r\n| - |\r\n| 1 |\r\n",
+			texts:  [][]string{{"a"}, {"1"}},
+			aligns: []Align{UnknownAlign},
+		},
+		{
+			name:   "skips fenced code",
+			s:      "```\n| x |\n|---|\n```\n| a |\n|---|\n",
+			texts:  [][]string{{"a"}},
+			aligns: []Align{UnknownAlign},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := ParseMarkdownTable(strings.NewReader(tt.s))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tableTexts(table.Rows); !reflect.DeepEqual(got, tt.texts) {
+				t.Errorf("rows = %q, want %q", got, tt.texts)
+			}
+			if !reflect.DeepEqual(table.Aligns, tt.aligns) {
+				t.Errorf("aligns = %v, want %v", table.Aligns, tt.aligns)
+			}
+			var caption string
+			if table.Caption != nil {
+				caption = table.Caption.Text
+			}
+			if caption != tt.caption {
+				t.Errorf("caption = %q, want %q", caption, tt.caption)
+			}
+			for idx, row := range table.Rows {
+				if row.Header != (idx == 0) {
+					t.Errorf("row %d header = %v", idx, row.Header)
+				}
+				for cellIdx, cell := range row.Cells {
+					if cell.TextFormat != Markdown {
+						t.Errorf("row %d cell %d text format = %v, want Markdown", idx, cellIdx, cell.TextFormat)
+					}
+					if align := tt.aligns[cellIdx]; align != UnknownAlign && (cell.Style == nil || cell.Style.Align != align) {
+						t.Errorf("row %d cell %d style = %+v, want align %v", idx, cellIdx, cell.Style, align)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseMarkdownTableErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"no table here",
+		"| a | b |\n| 1 | 2 |\n",
+		"| a | b |\n|---|\n",
+		"| a |\n|-x-|\n",
+	} {
+		if _, err := ParseMarkdownTable(strings.NewReader(s)); err == nil {
+			t.Errorf("ParseMarkdownTable(%q) got no error", s)
+		}
+	}
+}
+
+func TestParseMarkdownTables(t *testing.T) {
+	s := "## First\n| a |\n|---|\n| 1 |\n\n## Second\n| b | c |\n|---|--:|\n"
+	tables, err := ParseMarkdownTables(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("got %d tables, want 2", len(tables))
+	}
+	if tables[0].Caption.Text != "First" || tables[1].Caption.Text != "Second" {
+		t.Errorf("captions = %q, %q", tables[0].Caption.Text, tables[1].Caption.Text)
+	}
+	if got := tableTexts(tables[1].Rows); !reflect.DeepEqual(got, [][]string{{"b", "c"}}) {
+		t.Errorf("second table rows = %q", got)
+	}
+}
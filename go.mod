@@ -4,7 +4,10 @@ go 1.16
 
 require (
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
+	github.com/jung-kurt/gofpdf v1.16.2
 	github.com/llgcode/draw2d v0.0.0-20210313082411-577c1ead272a
 	github.com/mattn/go-runewidth v0.0.13
+	github.com/rivo/uniseg v0.4.7
 	golang.org/x/image v0.0.0-20210628002857-a66eb6448b8d
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,213 @@
+package tableimage
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Hyphenator find hyphenation points of words
+type Hyphenator interface {
+	// Hyphenate returns ascending byte offsets in text where it could be broken with a visible hyphen
+	Hyphenate(text string) []int
+}
+
+// HyphenatorFunc function implementing Hyphenator
+type HyphenatorFunc func(text string) []int
+
+// Hyphenate implement Hyphenator
+func (fn HyphenatorFunc) Hyphenate(text string) []int {
+	return fn(text)
+}
+
+// PatternHyphenator dictionary based hyphenator using Liang's TeX hyphenation patterns, like hyph-en-us.tex
+type PatternHyphenator struct {
+	// LeftMin minimum letters before a hyphen, 2 by default
+	LeftMin int
+	// RightMin minimum letters after a hyphen, 3 by default
+	RightMin   int
+	patterns   map[string][]int
+	exceptions map[string][]int
+	maxLength  int
+}
+
+// NewPatternHyphenator create hyphenator from TeX patterns like "1ba" or "a1b2c" and exceptions like "ta-ble"
+func NewPatternHyphenator(patterns []string, exceptions []string) *PatternHyphenator {
+	h := &PatternHyphenator{
+		LeftMin:    2,
+		RightMin:   3,
+		patterns:   make(map[string][]int, len(patterns)),
+		exceptions: make(map[string][]int, len(exceptions)),
+	}
+	for _, pattern := range patterns {
+		h.addPattern(pattern)
+	}
+	for _, exception := range exceptions {
+		h.addException(exception)
+	}
+	return h
+}
+
+// ReadHyphenationPatterns read TeX hyphenation file with \patterns{...} and \hyphenation{...} groups,
+// or plain lists where words with hyphens are exceptions, % starts a comment
+func ReadHyphenationPatterns(r io.Reader) (*PatternHyphenator, error) {
+	var (
+		patterns   []string
+		exceptions []string
+		group      string
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '%'); idx >= 0 {
+			line = line[:idx]
+		}
+		for _, token := range strings.Fields(line) {
+			switch {
+			case strings.HasPrefix(token, `\patterns{`):
+				group, token = "patterns", strings.TrimPrefix(token, `\patterns{`)
+			case strings.HasPrefix(token, `\hyphenation{`):
+				group, token = "hyphenation", strings.TrimPrefix(token, `\hyphenation{`)
+			case strings.HasPrefix(token, `\`):
+				continue
+			}
+			closed := strings.HasSuffix(token, "}")
+			token = strings.TrimSuffix(token, "}")
+			if token != "" {
+				patterns, exceptions = appendHyphenationToken(patterns, exceptions, token, group)
+			}
+			if closed {
+				group = ""
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewPatternHyphenator(patterns, exceptions), nil
+}
+
+// appendHyphenationToken add token to patterns or exceptions by its group
+func appendHyphenationToken(patterns []string, exceptions []string, token string, group string) ([]string, []string) {
+	if group == "hyphenation" || (group == "" && strings.Contains(token, "-")) {
+		return patterns, append(exceptions, token)
+	}
+	return append(patterns, token), exceptions
+}
+
+// addPattern add pattern like "a1b2c", digits are priorities of hyphenation between letters,
+// odd values allow and even values forbid a hyphen, "." marks word boundaries
+func (h *PatternHyphenator) addPattern(pattern string) {
+	var (
+		letters []rune
+		values  = []int{0}
+	)
+	for _, r := range strings.ToLower(pattern) {
+		if r >= '0' && r <= '9' {
+			values[len(values)-1] = int(r - '0')
+			continue
+		}
+		letters = append(letters, r)
+		values = append(values, 0)
+	}
+	if len(letters) == 0 {
+		return
+	}
+	h.patterns[string(letters)] = values
+	if len(letters) > h.maxLength {
+		h.maxLength = len(letters)
+	}
+}
+
+// addException add exception like "ta-ble" with explicit hyphenation points
+func (h *PatternHyphenator) addException(exception string) {
+	var (
+		letters []rune
+		values  = []int{0}
+	)
+	for _, r := range strings.ToLower(exception) {
+		if r == '-' {
+			values[len(values)-1] = 1
+			continue
+		}
+		letters = append(letters, r)
+		values = append(values, 0)
+	}
+	h.exceptions[string(letters)] = values
+}
+
+// Hyphenate implement Hyphenator, each run of letters in text is hyphenated as a word
+func (h *PatternHyphenator) Hyphenate(text string) []int {
+	var (
+		points []int
+		start  = -1
+	)
+	for idx, r := range text + " " {
+		if unicode.IsLetter(r) || unicode.Is(unicode.Mn, r) {
+			if start < 0 {
+				start = idx
+			}
+			continue
+		}
+		if start >= 0 {
+			points = append(points, h.hyphenateWord(text[start:idx], start)...)
+			start = -1
+		}
+	}
+	return points
+}
+
+// hyphenateWord hyphenation points of a word of letters, offset is added to byte offsets
+func (h *PatternHyphenator) hyphenateWord(word string, offset int) []int {
+	letters := []rune(strings.ToLower(word))
+	if len(letters) < h.LeftMin+h.RightMin || len(letters) != utf8.RuneCountInString(word) {
+		return nil
+	}
+	values, found := h.exceptions[string(letters)]
+	if !found {
+		values = h.liang(letters)
+	}
+	var (
+		points []int
+		pos    = offset
+	)
+	for idx, r := range []rune(word) {
+		pos += utf8.RuneLen(r)
+		breakAt := idx + 1
+		if breakAt < h.LeftMin || len(letters)-breakAt < h.RightMin {
+			continue
+		}
+		if values[breakAt]%2 == 1 {
+			points = append(points, pos)
+		}
+	}
+	return points
+}
+
+// liang Liang's algorithm, values between letters are the maximum of all matching patterns,
+// values[i] is the priority before letters[i]
+func (h *PatternHyphenator) liang(letters []rune) []int {
+	word := make([]rune, 0, len(letters)+2)
+	word = append(word, '.')
+	word = append(word, letters...)
+	word = append(word, '.')
+	values := make([]int, len(word)+1)
+	for start := range word {
+		for end := start + 1; end <= len(word) && end-start <= h.maxLength; end++ {
+			pattern, found := h.patterns[string(word[start:end])]
+			if !found {
+				continue
+			}
+			for idx, v := range pattern {
+				if v > values[start+idx] {
+					values[start+idx] = v
+				}
+			}
+		}
+	}
+	// drop the leading word boundary
+	return values[1 : len(letters)+2]
+}
@@ -0,0 +1,89 @@
+package tableimage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// knuthPatterns patterns hyphenating "hyphenation" from Liang's thesis
+var knuthPatterns = []string{"hy3ph", "he2n", "hena4", "hen5at", "1na", "n2at", "1tio", "2io", "o2n"}
+
+func TestPatternHyphenator(t *testing.T) {
+	h := NewPatternHyphenator(knuthPatterns, []string{"ta-ble"})
+	tests := []struct {
+		text string
+		want []int
+	}{
+		{"hyphenation", []int{2, 6}},
+		{"Hyphenation", []int{2, 6}},
+		{"HYPHENATION", []int{2, 6}},
+		{"table", []int{2}},
+		{"tables", nil},
+		{"über-hyphenation", []int{8, 12}},
+		{"hyphenation, hyphenation", []int{2, 6, 15, 19}},
+		{"hyphenation's", []int{2, 6}},
+		{"na", nil},
+		{"", nil},
+		{"1234", nil},
+	}
+	for _, tt := range tests {
+		if got := h.Hyphenate(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Hyphenate(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestPatternHyphenatorMin(t *testing.T) {
+	h := NewPatternHyphenator([]string{"1a", "1b", "1c", "1d", "1e", "1f"}, nil)
+	tests := []struct {
+		left  int
+		right int
+		want  []int
+	}{
+		{2, 3, []int{2, 3}},
+		{1, 1, []int{1, 2, 3, 4, 5}},
+		{3, 3, []int{3}},
+		{4, 3, nil},
+	}
+	for _, tt := range tests {
+		h.LeftMin, h.RightMin = tt.left, tt.right
+		if got := h.Hyphenate("abcdef"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("LeftMin %d RightMin %d Hyphenate(\"abcdef\") = %v, want %v", tt.left, tt.right, got, tt.want)
+		}
+	}
+}
+
+func TestReadHyphenationPatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+	}{
+		{"tex", `% hyphenation patterns
+\message{sample}
+\patterns{ % patterns group
+hy3ph he2n hena4
+hen5at 1na n2at
+1tio 2io o2n }
+\hyphenation{
+ta-ble
+}`},
+		{"plain", "hy3ph he2n hena4 hen5at\n1na n2at 1tio 2io o2n\n% exceptions\nta-ble\n"},
+		{"closing brace on token", `\patterns{hy3ph he2n hena4 hen5at 1na n2at 1tio 2io o2n}
+\hyphenation{ta-ble}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := ReadHyphenationPatterns(strings.NewReader(tt.s))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := h.Hyphenate("hyphenation"); !reflect.DeepEqual(got, []int{2, 6}) {
+				t.Errorf("Hyphenate(\"hyphenation\") = %v, want [2 6]", got)
+			}
+			if got := h.Hyphenate("table"); !reflect.DeepEqual(got, []int{2}) {
+				t.Errorf("Hyphenate(\"table\") = %v, want [2]", got)
+			}
+		})
+	}
+}
@@ -1,17 +1,22 @@
 package tableimage
 
 import (
+	"encoding/base64"
+	"errors"
 	"image"
+	"io"
 	"math"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 // Image image setting
 type Image struct {
-	// URL image link
+	// URL image link or data URI like data:image/png;base64,...
 	URL string `json:"url,omitempty"`
 	// Data image data
-	Data image.Image
+	Data image.Image `json:"-"`
 	// Inline display inline
 	Inline bool `json:"inline,omitempty"`
 	// Size image width/height
@@ -61,6 +66,9 @@ func (i *Image) Download() error {
 	if i.Data != nil || i.URL == "" {
 		return nil
 	}
+	if strings.HasPrefix(i.URL, "data:") {
+		return i.decodeDataURI()
+	}
 	resp, err := http.DefaultClient.Get(i.URL)
 	if err != nil {
 		return err
@@ -75,6 +83,52 @@ func (i *Image) Download() error {
 	return nil
 }
 
+// decodeDataURI decode image data from data URI
+func (i *Image) decodeDataURI() error {
+	reader, err := i.dataURIReader()
+	if err != nil {
+		return err
+	}
+	img, _, err := image.Decode(reader)
+	if err != nil {
+		return err
+	}
+	i.Data = img
+	i.UpdateSize()
+	return nil
+}
+
+// DecodeConfig decode color model and dimensions of a data URI image without decoding its pixels,
+// could be used to check image size before drawing
+func (i Image) DecodeConfig() (image.Config, error) {
+	if !strings.HasPrefix(i.URL, "data:") {
+		return image.Config{}, errors.New("image url is not a data uri")
+	}
+	reader, err := i.dataURIReader()
+	if err != nil {
+		return image.Config{}, err
+	}
+	cfg, _, err := image.DecodeConfig(reader)
+	return cfg, err
+}
+
+// dataURIReader reader of data URI payload
+func (i Image) dataURIReader() (io.Reader, error) {
+	idx := strings.IndexByte(i.URL, ',')
+	if idx < 0 {
+		return nil, errors.New("invalid data uri")
+	}
+	meta, payload := i.URL[len("data:"):idx], i.URL[idx+1:]
+	if strings.HasSuffix(meta, ";base64") {
+		return base64.NewDecoder(base64.StdEncoding, strings.NewReader(payload)), nil
+	}
+	decoded, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(decoded), nil
+}
+
 // UpdateSize update Size based on image bounds
 func (i *Image) UpdateSize() {
 	bounds := i.Data.Bounds()
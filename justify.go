@@ -0,0 +1,97 @@
+package tableimage
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// justifyLine split line into pieces at spaces and around wide glyphs, returns the pieces and the extra space
+// after each piece so that the line fills width. Trailing spaces are dropped, runs with padding or background
+// are kept whole, the line is left as is if it has no gaps or is wider than width.
+func justifyLine(line Word, width int, faces faceCache) ([]Text, []int) {
+	var pieces []Text
+	for _, txt := range trimLineEnd(line, faces) {
+		if txt.Padding > 0 || txt.BgColor != "" {
+			pieces = append(pieces, txt)
+			continue
+		}
+		face := faces.face(txt.Font)
+		var start int
+		for idx, r := range txt.Value {
+			end := idx + utf8.RuneLen(r)
+			if isWideRune(r) && idx > start {
+				pieces = append(pieces, TextFromText(txt.Value[start:idx], txt, face))
+				start = idx
+			}
+			if unicode.IsSpace(r) || isWideRune(r) {
+				pieces = append(pieces, TextFromText(txt.Value[start:end], txt, face))
+				start = end
+			}
+		}
+		if start < len(txt.Value) {
+			pieces = append(pieces, TextFromText(txt.Value[start:], txt, face))
+		}
+	}
+	var (
+		lineWidth int
+		gapsCount int
+		gaps      = make([]int, len(pieces))
+	)
+	for idx, piece := range pieces {
+		lineWidth += piece.Width
+		if idx < len(pieces)-1 && isJustifyGap(piece, pieces[idx+1]) {
+			gaps[idx] = 1
+			gapsCount++
+		}
+	}
+	extra := width - lineWidth
+	if gapsCount == 0 || extra <= 0 {
+		return line, nil
+	}
+	var gapIdx int
+	for idx, gap := range gaps {
+		if gap == 0 {
+			continue
+		}
+		gaps[idx] = extra / gapsCount
+		if gapIdx < extra%gapsCount {
+			gaps[idx]++
+		}
+		gapIdx++
+	}
+	return pieces, gaps
+}
+
+// trimLineEnd drop new line and trailing spaces of line
+func trimLineEnd(line Word, faces faceCache) Word {
+	trimmed := make(Word, len(line))
+	copy(trimmed, line)
+	for len(trimmed) > 0 {
+		last := trimmed[len(trimmed)-1]
+		value := strings.TrimRightFunc(last.Value, unicode.IsSpace)
+		if value == last.Value {
+			break
+		}
+		if value == "" {
+			trimmed = trimmed[:len(trimmed)-1]
+			continue
+		}
+		trimmed[len(trimmed)-1] = TextFromText(value, last, faces.face(last.Font))
+		break
+	}
+	return trimmed
+}
+
+// isJustifyGap check if extra space could be put between two pieces, after spaces and next to wide glyphs
+func isJustifyGap(piece Text, next Text) bool {
+	last, _ := utf8.DecodeLastRuneInString(piece.Value)
+	first, _ := utf8.DecodeRuneInString(next.Value)
+	return unicode.IsSpace(last) || isWideRune(last) || isWideRune(first)
+}
+
+func isWideRune(r rune) bool {
+	return runewidth.RuneWidth(r) == 2
+}
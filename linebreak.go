@@ -0,0 +1,369 @@
+package tableimage
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// softHyphen invisible hyphenation point, shows as hyphen if a line breaks at it
+	softHyphen = "\u00ad"
+	// newlines characters forcing a line break
+	newlines = "\r\n\v\f\u0085\u2028\u2029"
+	// maxHyphenateLength longest unit in bytes passed to hyphenator, longer text like URLs or encoded data isn't a word
+	// and is broken between grapheme clusters
+	maxHyphenateLength = 256
+)
+
+// lineUnit text between two line break opportunities
+type lineUnit struct {
+	// runs styled text runs, trailing spaces included
+	runs Word
+	// space width of trailing spaces, which may hang over the line end
+	space int
+	// softHyphen unit ends with a soft hyphen, a hyphen shows if the line breaks after it
+	softHyphen bool
+	// mandatory line must break after unit
+	mandatory bool
+}
+
+func (u lineUnit) width() int {
+	return u.runs.Width()
+}
+
+// textWidth width without trailing spaces
+func (u lineUnit) textWidth() int {
+	return u.runs.Width() - u.space
+}
+
+func (u lineUnit) text() string {
+	var b strings.Builder
+	for _, txt := range u.runs {
+		b.WriteString(txt.Value)
+	}
+	return b.String()
+}
+
+// breakUnits split styled runs into units at UAX #14 line break opportunities, which are found across run boundaries
+func breakUnits(segments []Text, faces faceCache) []lineUnit {
+	var b strings.Builder
+	for _, seg := range segments {
+		b.WriteString(seg.Value)
+	}
+	var (
+		units   []lineUnit
+		start   int
+		state   = -1
+		segment string
+	)
+	for rest := b.String(); rest != ""; {
+		segment, rest, _, state = uniseg.FirstLineSegmentInString(rest, state)
+		units = append(units, newLineUnit(segments, start, segment, faces))
+		start += len(segment)
+	}
+	return units
+}
+
+// newLineUnit unit of segment starting at byte offset start of runs text, new lines and soft hyphen are removed
+func newLineUnit(runs []Text, start int, segment string, faces faceCache) lineUnit {
+	var u lineUnit
+	body := strings.TrimRight(segment, newlines)
+	u.mandatory = len(body) < len(segment)
+	if strings.HasSuffix(body, softHyphen) {
+		body = strings.TrimSuffix(body, softHyphen)
+		u.softHyphen = true
+	}
+	u.runs = sliceRuns(runs, start, start+len(body), faces)
+	if trimmed := strings.TrimRightFunc(body, unicode.IsSpace); len(trimmed) < len(body) {
+		u.space = u.runs.Width() - prefixWidth(u.runs, len(trimmed), faces)
+	}
+	return u
+}
+
+// sliceRuns measured pieces of runs covering bytes from..to of runs text
+func sliceRuns(runs []Text, from int, to int, faces faceCache) Word {
+	var (
+		word Word
+		pos  int
+	)
+	for _, txt := range runs {
+		end := pos + len(txt.Value)
+		if end > from && pos < to {
+			start, stop := from-pos, to-pos
+			if start < 0 {
+				start = 0
+			}
+			if stop > len(txt.Value) {
+				stop = len(txt.Value)
+			}
+			word = append(word, TextFromText(txt.Value[start:stop], txt, faces.face(txt.Font)))
+		}
+		pos = end
+	}
+	return word
+}
+
+// prefixWidth width of the first n bytes of runs text
+func prefixWidth(runs Word, n int, faces faceCache) int {
+	var width, pos int
+	for _, txt := range runs {
+		end := pos + len(txt.Value)
+		if end <= n {
+			width += txt.Width
+		} else {
+			if n > pos {
+				width += txt.Padding + int(stringWidth(txt.Value[:n-pos], faces.face(txt.Font)))
+			}
+			break
+		}
+		pos = end
+	}
+	return width
+}
+
+// splitUnit split unit at byte offset n of its text
+func splitUnit(u lineUnit, n int, faces faceCache) (lineUnit, lineUnit) {
+	end := len(u.text())
+	head := lineUnit{
+		runs: sliceRuns(u.runs, 0, n, faces),
+	}
+	tail := u
+	tail.runs = sliceRuns(u.runs, n, end, faces)
+	if tail.space > tail.runs.Width() {
+		tail.space = tail.runs.Width()
+	}
+	return head, tail
+}
+
+// hyphenRun visible hyphen in style of the last run
+func hyphenRun(runs Word, faces faceCache) Text {
+	var txt Text
+	if len(runs) > 0 {
+		txt = runs[len(runs)-1]
+	}
+	return TextFromText("-", txt, faces.face(txt.Font))
+}
+
+// lineWrapper fill lines with units
+type lineWrapper struct {
+	w          int
+	hyphenator Hyphenator
+	faces      faceCache
+	lines      []Word
+	line       Word
+	// width line width including trailing spaces of last unit
+	width int
+	// softHyphen last unit of line ends with a soft hyphen
+	softHyphen bool
+}
+
+// wrapUnits wrap units into lines in w length, units which don't fit are hyphenated to fill the line if a hyphenator
+// is set, a unit wider than the line is broken between grapheme clusters
+func wrapUnits(units []lineUnit, w int, hyphenator Hyphenator, faces faceCache) []Word {
+	lw := &lineWrapper{
+		w:          w,
+		hyphenator: hyphenator,
+		faces:      faces,
+	}
+	for idx := 0; idx < len(units); {
+		u := units[idx]
+		if lw.fits(u) {
+			lw.add(u)
+			if u.mandatory {
+				lw.flush(true)
+			}
+			idx++
+			continue
+		}
+		if head, tail, ok := lw.hyphenate(u, lw.w-lw.width); ok {
+			lw.add(head)
+			lw.flush(false)
+			units[idx] = tail
+			continue
+		}
+		if len(lw.line) > 0 {
+			lw.flush(false)
+			continue
+		}
+		if heads, tail, ok := lw.breakGraphemes(u); ok {
+			for _, head := range heads {
+				lw.add(head)
+				lw.flush(false)
+			}
+			units[idx] = tail
+			continue
+		}
+		// a single grapheme cluster wider than the line overflows it
+		lw.add(u)
+		if u.mandatory {
+			lw.flush(true)
+		}
+		idx++
+	}
+	if len(lw.line) > 0 {
+		lw.flush(false)
+	}
+	return lw.lines
+}
+
+// unitLines put every unit on its own line
+func unitLines(units []lineUnit, faces faceCache) []Word {
+	lw := &lineWrapper{
+		faces: faces,
+	}
+	for _, u := range units {
+		lw.add(u)
+		lw.flush(u.mandatory)
+	}
+	return lw.lines
+}
+
+// fits check if unit fits in the rest of line, its trailing spaces may hang over the line end
+func (lw *lineWrapper) fits(u lineUnit) bool {
+	width := u.textWidth()
+	if u.softHyphen {
+		width += hyphenRun(u.runs, lw.faces).Width
+	}
+	return width == 0 || lw.width+width <= lw.w
+}
+
+func (lw *lineWrapper) add(u lineUnit) {
+	lw.line = append(lw.line, u.runs...)
+	lw.width += u.width()
+	lw.softHyphen = u.softHyphen
+}
+
+// flush finish line, a hyphen is added after a soft hyphen, trailing spaces are dropped,
+// lines ending with a mandatory break keep a zero width new line
+func (lw *lineWrapper) flush(mandatory bool) {
+	line := lw.line
+	if lw.softHyphen && !mandatory {
+		line = append(line, hyphenRun(line, lw.faces))
+	}
+	line = mergeRuns(trimLineEnd(line, lw.faces), lw.faces)
+	if mandatory {
+		var newline Text
+		if len(lw.line) > 0 {
+			newline = lw.line[len(lw.line)-1]
+		}
+		newline.Value = "\n"
+		newline.Width = 0
+		line = append(line, newline)
+	}
+	lw.lines = append(lw.lines, line)
+	lw.line = nil
+	lw.width = 0
+	lw.softHyphen = false
+}
+
+// hyphenate split unit at the last hyphenation point where the head and a hyphen fit in width
+func (lw *lineWrapper) hyphenate(u lineUnit, width int) (lineUnit, lineUnit, bool) {
+	if lw.hyphenator == nil {
+		return u, u, false
+	}
+	text := strings.TrimRightFunc(u.text(), unicode.IsSpace)
+	if len(text) > maxHyphenateLength {
+		return u, u, false
+	}
+	points := lw.hyphenator.Hyphenate(text)
+	for idx := len(points) - 1; idx >= 0; idx-- {
+		n := points[idx]
+		if n <= 0 || n >= len(text) {
+			continue
+		}
+		head, tail := splitUnit(u, n, lw.faces)
+		hyphen := hyphenRun(head.runs, lw.faces)
+		if head.width()+hyphen.Width <= width {
+			head.runs = append(head.runs, hyphen)
+			return head, tail, true
+		}
+	}
+	return u, u, false
+}
+
+// breakGraphemes emergency break of a unit wider than the line into full lines of whole grapheme clusters and the rest,
+// each line holds at least one cluster, false if the unit has a single cluster. Clusters are measured one by one into
+// a running width so that long unbroken text like URLs breaks in linear time.
+func (lw *lineWrapper) breakGraphemes(u lineUnit) ([]lineUnit, lineUnit, bool) {
+	var (
+		heads   []lineUnit
+		start   int
+		n       int
+		width   fixed.Int26_6
+		cluster string
+		// trailing spaces hang over the line end and never break
+		textEnd = len(strings.TrimRightFunc(u.text(), unicode.IsSpace))
+	)
+	for _, txt := range u.runs {
+		face := lw.faces.face(txt.Font)
+		width += fixed.I(txt.Padding)
+		state := -1
+		prev := rune(-1)
+		for rest := txt.Value; rest != ""; {
+			cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+			clusterWidth := clusterAdvance(face, prev, cluster)
+			if n > start && n < textEnd && (width+clusterWidth).Floor() > lw.w {
+				heads = append(heads, lineUnit{runs: sliceRuns(u.runs, start, n, lw.faces)})
+				start = n
+				// a line starting inside the run gets its padding again
+				width = fixed.I(txt.Padding)
+				clusterWidth = clusterAdvance(face, -1, cluster)
+			}
+			width += clusterWidth
+			n += len(cluster)
+			prev, _ = utf8.DecodeLastRuneInString(cluster)
+		}
+		width += fixed.I(txt.Padding)
+	}
+	if len(heads) == 0 {
+		return nil, u, false
+	}
+	tail := u
+	tail.runs = sliceRuns(u.runs, start, n, lw.faces)
+	if tail.space > tail.runs.Width() {
+		tail.space = tail.runs.Width()
+	}
+	return heads, tail, true
+}
+
+// clusterAdvance advance width of a grapheme cluster following rune prev, kerning included
+func clusterAdvance(face font.Face, prev rune, cluster string) fixed.Int26_6 {
+	if face == nil {
+		return 0
+	}
+	advance := font.MeasureString(face, cluster)
+	if prev >= 0 {
+		first, _ := utf8.DecodeRuneInString(cluster)
+		advance += face.Kern(prev, first)
+	}
+	return advance
+}
+
+// mergeRuns join adjacent runs of the same style
+func mergeRuns(line Word, faces faceCache) Word {
+	merged := make(Word, 0, len(line))
+	for _, txt := range line {
+		if last := len(merged) - 1; last >= 0 && merged[last].SameStyle(txt) {
+			merged[last] = TextFromText(merged[last].Value+txt.Value, merged[last], faces.face(txt.Font))
+			continue
+		}
+		merged = append(merged, txt)
+	}
+	return merged
+}
+
+// unitsMinWidth width of the widest unit without trailing spaces
+func unitsMinWidth(units []lineUnit) int {
+	var width int
+	for _, u := range units {
+		if u.textWidth() > width {
+			width = u.textWidth()
+		}
+	}
+	return width
+}
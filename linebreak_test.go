@@ -0,0 +1,184 @@
+package tableimage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// lineTexts text of each line
+func lineTexts(lines []Word) []string {
+	texts := make([]string, len(lines))
+	for idx, line := range lines {
+		for _, txt := range line {
+			texts[idx] += txt.Value
+		}
+	}
+	return texts
+}
+
+// textWidth measured width of s in base font
+func textWidth(t *testing.T, baseFont *Font, s string) int {
+	t.Helper()
+	return int(stringWidth(s, make(faceCache).face(baseFont)))
+}
+
+func TestWrap(t *testing.T) {
+	baseFont := newTestTableImage(t).style.Font
+	hyphenator := NewPatternHyphenator(knuthPatterns, nil)
+	tests := []struct {
+		name       string
+		s          string
+		fit        string
+		hyphenator Hyphenator
+		want       []string
+	}{
+		{
+			name: "spaces",
+			s:    "one two three",
+			fit:  "one two",
+			want: []string{"one two", "three"},
+		},
+		{
+			name: "trailing spaces hang",
+			s:    "one two   three",
+			fit:  "one two",
+			want: []string{"one two", "three"},
+		},
+		{
+			name: "new lines",
+			s:    "one\ntwo\r\nthree",
+			fit:  "one two three",
+			want: []string{"one\n", "two\n", "three"},
+		},
+		{
+			name: "no break space",
+			s:    "x 100 km",
+			fit:  "100 km",
+			want: []string{"x", "100 km"},
+		},
+		{
+			name: "soft hyphen",
+			s:    "con­tent",
+			fit:  "con-",
+			want: []string{"con-", "tent"},
+		},
+		{
+			name: "unused soft hyphen",
+			s:    "con­tent",
+			fit:  "content",
+			want: []string{"content"},
+		},
+		{
+			name:       "hyphenation",
+			s:          "hyphenation",
+			fit:        "hyphen-",
+			hyphenator: hyphenator,
+			want:       []string{"hyphen-", "ation"},
+		},
+		{
+			name:       "hyphenation after words",
+			s:          "the hyphenation",
+			fit:        "the hy-",
+			hyphenator: hyphenator,
+			want:       []string{"the hy-", "phen-", "ation"},
+		},
+		{
+			name: "url breaks after slash",
+			s:    "example.com/path/to",
+			fit:  "example.com/path/",
+			want: []string{"example.com/path/", "to"},
+		},
+		{
+			name: "ideographs",
+			s:    "漢字漢字",
+			fit:  "漢字",
+			want: []string{"漢字", "漢字"},
+		},
+		{
+			name: "single cluster overflows",
+			s:    "W",
+			fit:  "",
+			want: []string{"W"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := textWidth(t, baseFont, tt.fit)
+			lines, _ := wrap(tt.s, w, baseFont, Plain, tt.hyphenator)
+			if got := lineTexts(lines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrap(%q, %d) = %q, want %q", tt.s, w, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapNoWidth(t *testing.T) {
+	baseFont := newTestTableImage(t).style.Font
+	lines, maxWidth := wrap("one  1,234.5\ntwo-three", 0, baseFont, Plain, nil)
+	want := []string{"one", "1,234.5\n", "two-", "three"}
+	if got := lineTexts(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+	if want := textWidth(t, baseFont, "1,234.5"); maxWidth != want {
+		t.Errorf("max width = %d, want %d", maxWidth, want)
+	}
+}
+
+func TestWrapHardBreak(t *testing.T) {
+	baseFont := newTestTableImage(t).style.Font
+	lines, _ := wrap("one two\nthree", textWidth(t, baseFont, "three"), baseFont, Plain, nil)
+	want := []bool{false, true, false}
+	if len(lines) != len(want) {
+		t.Fatalf("lines = %q, want 3 lines", lineTexts(lines))
+	}
+	for idx, line := range lines {
+		if line.hardBreak() != want[idx] {
+			t.Errorf("line %d %q hard break = %v, want %v", idx, lineTexts(lines)[idx], line.hardBreak(), want[idx])
+		}
+	}
+	if newline := lines[1][len(lines[1])-1]; newline.Width != 0 {
+		t.Errorf("new line width = %d, want 0", newline.Width)
+	}
+}
+
+func TestWrapEmergencyBreak(t *testing.T) {
+	baseFont := newTestTableImage(t).style.Font
+	s := "https://example.com/" + strings.Repeat("abcdefghij", 20) + " end"
+	w := textWidth(t, baseFont, "abcdefghij")
+	lines, maxWidth := wrap(s, w, baseFont, Plain, nil)
+	if len(lines) < 20 {
+		t.Fatalf("got %d lines, want at least 20", len(lines))
+	}
+	if maxWidth > w {
+		t.Errorf("max width = %d, wider than %d", maxWidth, w)
+	}
+	for idx, line := range lines {
+		if line.Width() > w {
+			t.Errorf("line %d %q width = %d, wider than %d", idx, lineTexts(lines)[idx], line.Width(), w)
+		}
+	}
+	if got := strings.Join(lineTexts(lines), ""); got != strings.Replace(s, " ", "", 1) {
+		t.Errorf("joined lines = %q, want %q", got, s)
+	}
+}
+
+func TestWrapStyledRuns(t *testing.T) {
+	baseFont := newTestTableImage(t).style.Font
+	lines, _ := wrap("<b>bold</b>plain <i>italic</i>", textWidth(t, baseFont, "boldplain ital"), baseFont, InlineTags, nil)
+	if got := lineTexts(lines); !reflect.DeepEqual(got, []string{"boldplain", "italic"}) {
+		t.Fatalf("lines = %q", got)
+	}
+	if len(lines[0]) != 2 || lines[0][0].Font == lines[0][1].Font {
+		t.Errorf("first line runs = %+v, want bold and plain runs", lines[0])
+	}
+}
+
+func TestUnitsMinWidth(t *testing.T) {
+	baseFont := newTestTableImage(t).style.Font
+	faces := make(faceCache)
+	units := textUnits("a widest   b", baseFont, Plain, faces)
+	if got, want := unitsMinWidth(units), textWidth(t, baseFont, "widest"); got != want {
+		t.Errorf("unitsMinWidth = %d, want %d", got, want)
+	}
+}
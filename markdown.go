@@ -0,0 +1,254 @@
+package tableimage
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseMarkdown parse markdown inline formatting into a markup node tree, supports **strong**, __strong__,
+// *emphasis*, _emphasis_, ~~strikethrough~~, `code`, [link](url) and backslash escapes, unmatched delimiters are kept as text
+func ParseMarkdown(s string) *MarkupNode {
+	p := &markdownParser{s: s}
+	return &MarkupNode{
+		Pos:      [2]int{0, len(s)},
+		Children: p.inline(0, len(s)),
+	}
+}
+
+type markdownParser struct {
+	s string
+}
+
+// inline parse inline nodes in s[start:end]
+func (p *markdownParser) inline(start int, end int) []*MarkupNode {
+	var (
+		nodes     []*MarkupNode
+		text      strings.Builder
+		textStart int
+	)
+	flush := func(pos int) {
+		if text.Len() == 0 {
+			return
+		}
+		nodes = append(nodes, &MarkupNode{
+			Text: text.String(),
+			Pos:  [2]int{textStart, pos},
+		})
+		text.Reset()
+	}
+	write := func(pos int, s string) {
+		if text.Len() == 0 {
+			textStart = pos
+		}
+		text.WriteString(s)
+	}
+	for i := start; i < end; {
+		c := p.s[i]
+		switch c {
+		case '\\':
+			if i+1 < end && isMarkdownPunct(p.s[i+1]) {
+				write(i, p.s[i+1:i+2])
+				i += 2
+				continue
+			}
+		case '`':
+			n := p.run(i, end)
+			if closing := p.codeEnd(i+n, end, n); closing >= 0 {
+				flush(i)
+				nodes = append(nodes, &MarkupNode{
+					Tag: "code",
+					Pos: [2]int{i, closing + n},
+					Children: []*MarkupNode{{
+						Text: trimCodeSpan(p.s[i+n : closing]),
+						Pos:  [2]int{i + n, closing},
+					}},
+				})
+				i = closing + n
+				continue
+			}
+			write(i, p.s[i:i+n])
+			i += n
+			continue
+		case '[':
+			if textEnd, url, next, ok := p.link(i, end); ok {
+				flush(i)
+				nodes = append(nodes, &MarkupNode{
+					Tag:      "a",
+					Attrs:    map[string]string{"href": url},
+					Pos:      [2]int{i, next},
+					Children: p.inline(i+1, textEnd),
+				})
+				i = next
+				continue
+			}
+		case '*', '_', '~':
+			if node, next, ok := p.emphasis(i, end); ok {
+				flush(i)
+				nodes = append(nodes, node)
+				i = next
+				continue
+			}
+			if n := p.run(i, end); n > 1 {
+				write(i, p.s[i:i+n])
+				i += n
+				continue
+			}
+		}
+		write(i, p.s[i:i+1])
+		i++
+	}
+	flush(end)
+	return nodes
+}
+
+// run length of the run of same delimiter chars at i
+func (p *markdownParser) run(i int, end int) int {
+	n := 1
+	for i+n < end && p.s[i+n] == p.s[i] {
+		n++
+	}
+	return n
+}
+
+// codeEnd find the closing backtick run of exactly n backticks, returns -1 if not found
+func (p *markdownParser) codeEnd(start int, end int, n int) int {
+	for i := start; i < end; {
+		if p.s[i] != '`' {
+			i++
+			continue
+		}
+		l := p.run(i, end)
+		if l == n {
+			return i
+		}
+		i += l
+	}
+	return -1
+}
+
+// link parse [text](url) at i, returns end of link text, url and position after the link
+func (p *markdownParser) link(i int, end int) (int, string, int, bool) {
+	depth := 0
+	for j := i; j < end; j++ {
+		switch p.s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if j+1 >= end || p.s[j+1] != '(' {
+				return 0, "", 0, false
+			}
+			closing := strings.IndexByte(p.s[j+2:end], ')')
+			if closing < 0 {
+				return 0, "", 0, false
+			}
+			url := strings.TrimSpace(p.s[j+2 : j+2+closing])
+			return j, url, j + 3 + closing, true
+		}
+	}
+	return 0, "", 0, false
+}
+
+// emphasis parse emphasis, strong or strikethrough span opening at i
+func (p *markdownParser) emphasis(i int, end int) (*MarkupNode, int, bool) {
+	c := p.s[i]
+	n := p.run(i, end)
+	size := 1
+	if n >= 2 {
+		size = 2
+	}
+	if c == '~' && size != 2 {
+		return nil, 0, false
+	}
+	contentStart := i + size
+	if contentStart >= end || isMarkdownSpace(p.s[contentStart]) {
+		return nil, 0, false
+	}
+	if c == '_' && i > 0 && isMarkdownWordChar(p.s, i-1) {
+		return nil, 0, false
+	}
+	closing := p.emphasisEnd(c, size, contentStart+1, end)
+	if closing < 0 {
+		return nil, 0, false
+	}
+	tag := "i"
+	switch {
+	case c == '~':
+		tag = "s"
+	case size == 2:
+		tag = "b"
+	}
+	return &MarkupNode{
+		Tag:      tag,
+		Pos:      [2]int{i, closing + size},
+		Children: p.inline(contentStart, closing),
+	}, closing + size, true
+}
+
+// emphasisEnd find closing delimiter of size chars, skipping escapes and code spans, returns -1 if not found
+func (p *markdownParser) emphasisEnd(c byte, size int, start int, end int) int {
+	for j := start; j < end; {
+		switch p.s[j] {
+		case '\\':
+			j += 2
+			continue
+		case '`':
+			n := p.run(j, end)
+			if closing := p.codeEnd(j+n, end, n); closing >= 0 {
+				j = closing + n
+			} else {
+				j += n
+			}
+			continue
+		case c:
+			n := p.run(j, end)
+			closing := j + n - size
+			if n < size || size == 1 && n%2 == 0 || isMarkdownSpace(p.s[j-1]) {
+				j += n
+				continue
+			}
+			if c == '_' && j+n < end && isMarkdownWordChar(p.s, j+n) {
+				j += n
+				continue
+			}
+			return closing
+		}
+		j++
+	}
+	return -1
+}
+
+// trimCodeSpan strip one leading and trailing space of code span content if both present
+func trimCodeSpan(s string) string {
+	if len(s) > 2 && s[0] == ' ' && s[len(s)-1] == ' ' && strings.TrimSpace(s) != "" {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// isMarkdownPunct check if c is an ascii punctuation which could be backslash escaped
+func isMarkdownPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isMarkdownSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isMarkdownWordChar check if rune ending or starting at byte i is a letter or digit
+func isMarkdownWordChar(s string, i int) bool {
+	if s[i] < utf8.RuneSelf {
+		return unicode.IsLetter(rune(s[i])) || unicode.IsDigit(rune(s[i]))
+	}
+	r, _ := utf8.DecodeRuneInString(s[i:])
+	if r == utf8.RuneError {
+		r, _ = utf8.DecodeLastRuneInString(s[:i+1])
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,53 @@
+package tableimage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want []Text
+	}{
+		{"plain", "hello world", []Text{{Value: "hello world"}}},
+		{"strong", "a **b** c", []Text{{Value: "a "}, {Value: "b", Bold: true}, {Value: " c"}}},
+		{"strong underscores", "__b__", []Text{{Value: "b", Bold: true}}},
+		{"emphasis", "*i* _j_", []Text{{Value: "i", Italic: true}, {Value: " "}, {Value: "j", Italic: true}}},
+		{"strong emphasis", "***x***", []Text{{Value: "x", Bold: true, Italic: true}}},
+		{"nested", "**a *b***", []Text{{Value: "a ", Bold: true}, {Value: "b", Bold: true, Italic: true}}},
+		{"strikethrough", "~~s~~", []Text{{Value: "s", Strike: true}}},
+		{"code", "`a*b*`", []Text{{Value: "a*b*", Mono: true, BgColor: DefaultCodeBgColor}}},
+		{"code double backticks", "`` a`b ``", []Text{{Value: "a`b", Mono: true, BgColor: DefaultCodeBgColor}}},
+		{"link", "[x](https://example.com)", []Text{{Value: "x", Underline: true, Color: DefaultLinkColor}}},
+		{"escapes", `\*a\* \_b\_`, []Text{{Value: "*a* _b_"}}},
+		{"unmatched", "a ** b", []Text{{Value: "a ** b"}}},
+		{"unclosed", "*a", []Text{{Value: "*a"}}},
+		{"intraword underscore", "snake_case_name", []Text{{Value: "snake_case_name"}}},
+		{"intraword star", "a*b*c", []Text{{Value: "a"}, {Value: "b", Italic: true}, {Value: "c"}}},
+		{"unclosed code", "`a", []Text{{Value: "`a"}}},
+		{"not a link", "[x] (y)", []Text{{Value: "[x] (y)"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeTexts(runsWithoutPos(ParseMarkdown(tt.s).Runs()))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMarkdown(%q) runs = %+v, want %+v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+// mergeTexts join adjacent runs of the same style, text nodes may be split at delimiters which stay text
+func mergeTexts(runs []Text) []Text {
+	var ret []Text
+	for _, txt := range runs {
+		if last := len(ret) - 1; last >= 0 && ret[last].SameStyle(txt) {
+			ret[last].Value += txt.Value
+			continue
+		}
+		ret = append(ret, txt)
+	}
+	return ret
+}
@@ -0,0 +1,385 @@
+package tableimage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// markupTags supported inline markup tags
+var markupTags = map[string]bool{
+	"text": true,
+	"b":    true,
+	"i":    true,
+	"u":    true,
+	"s":    true,
+	"code": true,
+	"a":    true,
+}
+
+// markupEntities supported named character references
+var markupEntities = map[string]string{
+	"lt":   "<",
+	"gt":   ">",
+	"amp":  "&",
+	"quot": "\"",
+	"apos": "'",
+	"nbsp": " ",
+}
+
+// MarkupError inline markup parse error
+type MarkupError struct {
+	// Text the parsed text
+	Text string
+	// Pos byte offset of the error in text
+	Pos int
+	// Msg error message
+	Msg string
+}
+
+// Error implement error interface
+func (e *MarkupError) Error() string {
+	return fmt.Sprintf("markup: %s at offset %d", e.Msg, e.Pos)
+}
+
+// MarkupNode inline markup tree node, text nodes have no Tag, the root node has neither Tag nor Text
+type MarkupNode struct {
+	// Tag element tag name
+	Tag string
+	// Attrs element attributes
+	Attrs map[string]string
+	// Text text node content with entities decoded
+	Text string
+	// Pos byte range of node in source text
+	Pos [2]int
+	// Children child nodes of element
+	Children []*MarkupNode
+}
+
+// Runs flatten markup tree into styled text runs, nested elements inherit styles of their parents
+func (n *MarkupNode) Runs() []Text {
+	return n.runs(Text{}, nil)
+}
+
+func (n *MarkupNode) runs(style Text, ret []Text) []Text {
+	if n.Tag == "" && len(n.Children) == 0 {
+		if n.Text != "" {
+			txt := style
+			txt.Value = n.Text
+			txt.Pos = n.Pos
+			ret = append(ret, txt)
+		}
+		return ret
+	}
+	style = n.apply(style)
+	for _, child := range n.Children {
+		ret = child.runs(style, ret)
+	}
+	return ret
+}
+
+// apply element style on top of parent style
+func (n *MarkupNode) apply(style Text) Text {
+	switch n.Tag {
+	case "b":
+		style.Bold = true
+	case "i":
+		style.Italic = true
+	case "u":
+		style.Underline = true
+	case "s":
+		style.Strike = true
+	case "code":
+		style.Mono = true
+		if style.BgColor == "" {
+			style.BgColor = DefaultCodeBgColor
+		}
+	case "a":
+		style.Underline = true
+		if style.Color == "" {
+			style.Color = DefaultLinkColor
+		}
+	}
+	for k, v := range n.Attrs {
+		switch k {
+		case "color":
+			style.Color = v
+		case "bgcolor":
+			style.BgColor = v
+		case "padding":
+			style.Padding, _ = strconv.Atoi(v)
+		case "font":
+			style.FontName = v
+		case "size":
+			style.FontSize, _ = strconv.ParseFloat(v, 64)
+		}
+	}
+	return style
+}
+
+// ParseMarkup parse inline markup into a node tree, returns *MarkupError for malformed markup.
+// A '<' which doesn't start a tag and an '&' which doesn't start a known entity are kept as text.
+func ParseMarkup(s string) (*MarkupNode, error) {
+	p := &markupParser{s: s}
+	return p.parse()
+}
+
+type markupParser struct {
+	s   string
+	pos int
+}
+
+func (p *markupParser) parse() (*MarkupNode, error) {
+	var (
+		root      = &MarkupNode{Pos: [2]int{0, len(p.s)}}
+		stack     = []*MarkupNode{root}
+		text      strings.Builder
+		textStart int
+	)
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		top.Children = append(top.Children, &MarkupNode{
+			Text: text.String(),
+			Pos:  [2]int{textStart, p.pos},
+		})
+		text.Reset()
+	}
+	for p.pos < len(p.s) {
+		if text.Len() == 0 {
+			textStart = p.pos
+		}
+		switch {
+		case p.isEndTag():
+			flush()
+			start := p.pos
+			name, err := p.endTag()
+			if err != nil {
+				return nil, err
+			}
+			top := stack[len(stack)-1]
+			if top == root {
+				return nil, p.errorf(start, "unexpected closing tag </%s>", name)
+			}
+			if top.Tag != name {
+				return nil, p.errorf(start, "unexpected closing tag </%s>, expecting </%s>", name, top.Tag)
+			}
+			top.Pos[1] = p.pos
+			stack = stack[:len(stack)-1]
+		case p.isStartTag():
+			flush()
+			node, selfClosing, err := p.startTag()
+			if err != nil {
+				return nil, err
+			}
+			top := stack[len(stack)-1]
+			top.Children = append(top.Children, node)
+			if !selfClosing {
+				stack = append(stack, node)
+			}
+		case p.s[p.pos] == '&':
+			value, n := decodeEntity(p.s[p.pos:])
+			text.WriteString(value)
+			p.pos += n
+		default:
+			text.WriteByte(p.s[p.pos])
+			p.pos++
+		}
+	}
+	flush()
+	if top := stack[len(stack)-1]; top != root {
+		return nil, p.errorf(top.Pos[0], "unclosed tag <%s>", top.Tag)
+	}
+	return root, nil
+}
+
+func (p *markupParser) errorf(pos int, format string, args ...interface{}) *MarkupError {
+	return &MarkupError{
+		Text: p.s,
+		Pos:  pos,
+		Msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *markupParser) isStartTag() bool {
+	return p.pos+1 < len(p.s) && p.s[p.pos] == '<' && isMarkupLetter(p.s[p.pos+1])
+}
+
+func (p *markupParser) isEndTag() bool {
+	return p.pos+2 < len(p.s) && p.s[p.pos] == '<' && p.s[p.pos+1] == '/' && isMarkupLetter(p.s[p.pos+2])
+}
+
+// startTag parse start tag with attributes, p.pos at '<'
+func (p *markupParser) startTag() (*MarkupNode, bool, error) {
+	start := p.pos
+	p.pos++
+	name := p.name()
+	if !markupTags[name] {
+		return nil, false, p.errorf(start, "unknown tag <%s>", name)
+	}
+	node := &MarkupNode{
+		Tag: name,
+		Pos: [2]int{start, len(p.s)},
+	}
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.s) {
+			return nil, false, p.errorf(start, "unterminated tag <%s>", name)
+		}
+		if p.s[p.pos] == '>' {
+			p.pos++
+			return node, false, nil
+		}
+		if strings.HasPrefix(p.s[p.pos:], "/>") {
+			p.pos += 2
+			node.Pos[1] = p.pos
+			return node, true, nil
+		}
+		attrStart := p.pos
+		attr := p.name()
+		if attr == "" {
+			r, _ := utf8.DecodeRuneInString(p.s[p.pos:])
+			return nil, false, p.errorf(p.pos, "unexpected %q in tag <%s>", r, name)
+		}
+		p.skipSpaces()
+		if p.pos >= len(p.s) || p.s[p.pos] != '=' {
+			return nil, false, p.errorf(attrStart, "missing value of attribute %s in tag <%s>", attr, name)
+		}
+		p.pos++
+		p.skipSpaces()
+		value, err := p.attrValue()
+		if err != nil {
+			return nil, false, err
+		}
+		if node.Attrs == nil {
+			node.Attrs = make(map[string]string)
+		}
+		node.Attrs[attr] = value
+	}
+}
+
+// endTag parse end tag, p.pos at '<'
+func (p *markupParser) endTag() (string, error) {
+	start := p.pos
+	p.pos += 2
+	name := p.name()
+	p.skipSpaces()
+	if p.pos >= len(p.s) || p.s[p.pos] != '>' {
+		return "", p.errorf(start, "unterminated closing tag </%s>", name)
+	}
+	p.pos++
+	return name, nil
+}
+
+// attrValue parse quoted or unquoted attribute value, unquoted values may contain spaces inside parentheses like rgba(0, 0, 0, 0.5)
+func (p *markupParser) attrValue() (string, error) {
+	start := p.pos
+	if p.pos >= len(p.s) {
+		return "", p.errorf(start, "missing attribute value")
+	}
+	if quote := p.s[p.pos]; quote == '"' || quote == '\'' {
+		end := strings.IndexByte(p.s[p.pos+1:], quote)
+		if end < 0 {
+			return "", p.errorf(start, "unterminated attribute value")
+		}
+		value := p.s[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return decodeEntities(value), nil
+	}
+	var depth int
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if depth == 0 && (isMarkupSpace(c) || c == '>' || strings.HasPrefix(p.s[p.pos:], "/>")) {
+			break
+		}
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf(start, "missing attribute value")
+	}
+	return decodeEntities(p.s[start:p.pos]), nil
+}
+
+// name parse tag or attribute name
+func (p *markupParser) name() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if !isMarkupLetter(c) && !(c >= '0' && c <= '9') && c != '-' && c != '_' {
+			break
+		}
+		p.pos++
+	}
+	return strings.ToLower(p.s[start:p.pos])
+}
+
+func (p *markupParser) skipSpaces() {
+	for p.pos < len(p.s) && isMarkupSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+// decodeEntities decode character references in s
+func decodeEntities(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] != '&' {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		value, n := decodeEntity(s[i:])
+		b.WriteString(value)
+		i += n
+	}
+	return b.String()
+}
+
+// decodeEntity decode character reference at start of s, returns decoded value and bytes consumed,
+// unknown references are kept as a literal '&'
+func decodeEntity(s string) (string, int) {
+	end := strings.IndexByte(s, ';')
+	if end < 2 || end > 10 {
+		return "&", 1
+	}
+	name := s[1:end]
+	if value, found := markupEntities[name]; found {
+		return value, end + 1
+	}
+	if name[0] != '#' {
+		return "&", 1
+	}
+	var (
+		code uint64
+		err  error
+	)
+	if len(name) > 1 && (name[1] == 'x' || name[1] == 'X') {
+		code, err = strconv.ParseUint(name[2:], 16, 32)
+	} else {
+		code, err = strconv.ParseUint(name[1:], 10, 32)
+	}
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return "&", 1
+	}
+	return string(rune(code)), end + 1
+}
+
+func isMarkupLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isMarkupSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,93 @@
+package tableimage
+
+import (
+	"reflect"
+	"testing"
+)
+
+// runsWithoutPos runs with source positions cleared for comparison
+func runsWithoutPos(runs []Text) []Text {
+	ret := make([]Text, len(runs))
+	for idx, txt := range runs {
+		txt.Pos = [2]int{}
+		ret[idx] = txt
+	}
+	return ret
+}
+
+func TestParseMarkup(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want []Text
+	}{
+		{"plain", "hello", []Text{{Value: "hello"}}},
+		{"empty", "", nil},
+		{"bold", "a <b>b</b> c", []Text{{Value: "a "}, {Value: "b", Bold: true}, {Value: " c"}}},
+		{"nested", "<b>x<i>y</i></b>", []Text{{Value: "x", Bold: true}, {Value: "y", Bold: true, Italic: true}}},
+		{"underline strike", "<u>u</u><s>s</s>", []Text{{Value: "u", Underline: true}, {Value: "s", Strike: true}}},
+		{"code", "<code>x</code>", []Text{{Value: "x", Mono: true, BgColor: DefaultCodeBgColor}}},
+		{"link", `<a href="https://example.com">x</a>`, []Text{{Value: "x", Underline: true, Color: DefaultLinkColor}}},
+		{"text attributes", `<text color="#ff0000" bgcolor='#00ff00' padding="2" font="Roboto" size="16">x</text>`, []Text{{Value: "x", Color: "#ff0000", BgColor: "#00ff00", Padding: 2, FontName: "Roboto", FontSize: 16}}},
+		{"entities", "&lt;b&gt; &amp; &quot;&apos;", []Text{{Value: `<b> & "'`}}},
+		{"unknown entity", "a &foo; b", []Text{{Value: "a &foo; b"}}},
+		{"less than", "1 < 2", []Text{{Value: "1 < 2"}}},
+		{"self closing", "a<b/>b", []Text{{Value: "a"}, {Value: "b"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ParseMarkup(tt.s)
+			if err != nil {
+				t.Fatalf("ParseMarkup(%q) error %v", tt.s, err)
+			}
+			if got := runsWithoutPos(root.Runs()); !reflect.DeepEqual(got, runsWithoutPos(tt.want)) {
+				t.Errorf("ParseMarkup(%q) runs = %+v, want %+v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMarkupPos(t *testing.T) {
+	s := "ab<b>cd</b>"
+	root, err := ParseMarkup(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runs := root.Runs()
+	want := [][2]int{{0, 2}, {5, 7}}
+	if len(runs) != len(want) {
+		t.Fatalf("runs = %+v, want %d runs", runs, len(want))
+	}
+	for idx, txt := range runs {
+		if txt.Pos != want[idx] || s[txt.Pos[0]:txt.Pos[1]] != txt.Value {
+			t.Errorf("run %q pos = %v, want %v", txt.Value, txt.Pos, want[idx])
+		}
+	}
+}
+
+func TestParseMarkupErrors(t *testing.T) {
+	tests := []struct {
+		s   string
+		pos int
+	}{
+		{"<b>x", 0},
+		{"x</b>", 1},
+		{"<b>x</i>", 4},
+		{"<foo>x</foo>", 0},
+		{"<b", 0},
+		{`<text color>x</text>`, 6},
+		{`<text color="red>x</text>`, 12},
+		{"<b>x<i>y</b></i>", 8},
+	}
+	for _, tt := range tests {
+		_, err := ParseMarkup(tt.s)
+		merr, ok := err.(*MarkupError)
+		if !ok {
+			t.Errorf("ParseMarkup(%q) error = %v, want *MarkupError", tt.s, err)
+			continue
+		}
+		if merr.Pos != tt.pos || merr.Text != tt.s {
+			t.Errorf("ParseMarkup(%q) error at %d, want %d: %v", tt.s, merr.Pos, tt.pos, merr)
+		}
+	}
+}
@@ -168,6 +168,13 @@ func WithFont(font *truetype.Font) Option {
 	})
 }
 
+// WithFontFile set font from truetype font file content, unlike WithFont the file could be embedded in PDF output
+func WithFontFile(file []byte) Option {
+	return optionFunc(func(ti *TableImage) {
+		ti.fontFile = file
+	})
+}
+
 // WithDPI set font dpi
 func WithDPI(dpi int) Option {
 	return optionFunc(func(ti *TableImage) {
@@ -181,7 +188,8 @@ func WithDPI(dpi int) Option {
 	})
 }
 
-// WithFontCache set font cache
+// WithFontCache set font cache, a cache shared by TableImages avoids reloading fonts from font folder,
+// PDF output embeds fonts of a FontFileCache like FolderFontCache
 func WithFontCache(cache draw2d.FontCache) Option {
 	return optionFunc(func(ti *TableImage) {
 		ti.fontCache = cache
@@ -194,3 +202,85 @@ func WithStyle(style *Style) Option {
 		ti.style = style
 	})
 }
+
+// WithHeaderRows set count of leading header rows repeated on every page
+func WithHeaderRows(rows int) Option {
+	return optionFunc(func(ti *TableImage) {
+		ti.headerRows = rows
+	})
+}
+
+// WithPageNumber set page number footer format for pages, format receives page number and total pages, e.g. "page %d of %d"
+func WithPageNumber(format string) Option {
+	return optionFunc(func(ti *TableImage) {
+		ti.pageNumber = format
+	})
+}
+
+// WithHeaderStyle set header rows style, inherits table style
+func WithHeaderStyle(style *Style) Option {
+	return optionFunc(func(ti *TableImage) {
+		ti.headerStyle = style
+	})
+}
+
+// WithColumns set column width and style specifications, columns without width setting are sized by content
+func WithColumns(columns ...ColumnSpec) Option {
+	return optionFunc(func(ti *TableImage) {
+		ti.columns = columns
+	})
+}
+
+// WithTableWidth set exact table width, column percentages and flex weights are resolved against it
+// and other columns shrink or grow proportionally, text is rewrapped to fit the columns
+func WithTableWidth(width int) Option {
+	return optionFunc(func(ti *TableImage) {
+		ti.tableWidth = width
+	})
+}
+
+// WithColumnStylePrecedence set precedence between column and row styles, column style overrides row style by default
+func WithColumnStylePrecedence(precedence StylePrecedence) Option {
+	return optionFunc(func(ti *TableImage) {
+		ti.columnPrecedence = precedence
+	})
+}
+
+// WithRules add conditional formatting rules, applied to non header cells before style inheritance, later rules take precedence
+func WithRules(rules ...Rule) Option {
+	return optionFunc(func(ti *TableImage) {
+		ti.rules = append(ti.rules, rules...)
+	})
+}
+
+// WithStripes set zebra striping background color for every other group of rows
+func WithStripes(every int, bgColor string) Option {
+	return optionFunc(func(ti *TableImage) {
+		ti.rules = append(ti.rules, StripeRule{
+			Every: every,
+			Style: &Style{BgColor: bgColor},
+		})
+	})
+}
+
+// WithStrictMarkup make drawing fail with *MarkupError on malformed inline markup instead of rendering it as plain text
+func WithStrictMarkup() Option {
+	return optionFunc(func(ti *TableImage) {
+		ti.strictMarkup = true
+	})
+}
+
+// WithTextFormat set default text format of cells, caption and footer, cells could override it with Cell.TextFormat
+func WithTextFormat(format TextFormat) Option {
+	return optionFunc(func(ti *TableImage) {
+		ti.textFormat = format
+	})
+}
+
+// WithHyphenator set hyphenator breaking words which don't fit the rest of a line with a visible hyphen,
+// like NewPatternHyphenator or ReadHyphenationPatterns with TeX patterns of the text language
+func WithHyphenator(h Hyphenator) Option {
+	return optionFunc(func(ti *TableImage) {
+		ti.hyphenator = h
+	})
+}
@@ -0,0 +1,188 @@
+package tableimage
+
+import (
+	"fmt"
+	"image"
+)
+
+// DrawPages draw table into pages no taller than maxHeight, rows are split at row boundaries,
+// leading Header rows or WithHeaderRows rows repeat on every page, caption is drawn on the first page and footer on the last one
+func (ti *TableImage) DrawPages(rows []Row, caption *Cell, footer *Cell, maxHeight int) ([]*image.RGBA, error) {
+	table, err := NewTable(ti, rows, caption, footer)
+	if err != nil {
+		return nil, err
+	}
+	pages := ti.Paginate(table, maxHeight)
+	imgs := make([]*image.RGBA, 0, len(pages))
+	for idx, page := range pages {
+		imgs = append(imgs, ti.drawPage(page, idx+1, len(pages)))
+	}
+	return imgs, nil
+}
+
+// Paginate split table into page tables no taller than maxHeight, a group of rows joined by row spans
+// taller than a page gets a page of its own
+func (ti *TableImage) Paginate(table *Table, maxHeight int) []*Table {
+	headers := table.HeaderRows()
+	if ti.headerRows > headers {
+		headers = ti.headerRows
+	}
+	if headers > len(table.rows) {
+		headers = len(table.rows)
+	}
+	segments := table.rowSegments(headers)
+	if len(segments) == 0 {
+		return []*Table{table}
+	}
+	var (
+		reserved     = ti.BorderSize().Y + ti.pageNumberSize(table).Y + sumInts(table.rowsHeight[:headers])
+		segmentsRows = func(from int, to int) (int, int) {
+			return segments[from][0], segments[to-1][1]
+		}
+		fits = func(from int, to int, first bool, last bool) bool {
+			start, end := segmentsRows(from, to)
+			height := reserved + sumInts(table.rowsHeight[start:end])
+			if first {
+				height += table.captionSize.Y
+			}
+			if last {
+				height += table.footerSize.Y
+			}
+			return height <= maxHeight
+		}
+		pages [][2]int
+		from  int
+	)
+	for idx := range segments {
+		if idx > from && !fits(from, idx+1, len(pages) == 0, false) {
+			pages = append(pages, [2]int{from, idx})
+			from = idx
+		}
+	}
+	pages = append(pages, [2]int{from, len(segments)})
+	last := pages[len(pages)-1]
+	if !fits(last[0], last[1], len(pages) == 1, true) && last[1]-last[0] > 1 {
+		split := last[1] - 1
+		for split-1 > last[0] && fits(split-1, last[1], false, true) {
+			split--
+		}
+		pages[len(pages)-1] = [2]int{last[0], split}
+		pages = append(pages, [2]int{split, last[1]})
+	}
+	ret := make([]*Table, 0, len(pages))
+	for idx, page := range pages {
+		start, end := segmentsRows(page[0], page[1])
+		ret = append(ret, table.page(headers, start, end, idx == 0, idx == len(pages)-1))
+	}
+	return ret
+}
+
+func (ti *TableImage) drawPage(table *Table, pageNum int, pages int) *image.RGBA {
+	tableSize := ti.Size(table)
+	pageNumber := ti.pageNumberCell(table, pageNum, pages)
+	var pageNumberSize image.Point
+	if pageNumber != nil {
+		pageNumberSize = pageNumber.Size()
+	}
+	img := image.NewRGBA(image.Rect(0, 0, tableSize.X, tableSize.Y+pageNumberSize.Y))
+	r := NewRasterRenderer(img)
+	if ti.style != nil && ti.style.BgColor != "" {
+		r.FillRect(img.Bounds(), ti.style.BgColor)
+	}
+	ti.draw(r, table)
+	if pageNumber != nil {
+		start := ti.innerStartPoint()
+		bounds := image.Rect(start.X, tableSize.Y, start.X+table.Size().X, tableSize.Y+pageNumberSize.Y)
+		pageNumber.Draw(r, bounds)
+	}
+	return img
+}
+
+// pageNumberCell page number footer cell, nil if page number is not enabled
+func (ti *TableImage) pageNumberCell(table *Table, pageNum int, pages int) *Cell {
+	if ti.pageNumber == "" {
+		return nil
+	}
+	style := &Style{}
+	if ti.style != nil && ti.style.Font != nil {
+		font := *ti.style.Font
+		style.Font = &font
+	}
+	style.Inherit(DefaultFooterStyle(), ti.fontCache)
+	style.MaxWidth = table.Size().X - style.BorderPadding().Size().X
+	return &Cell{
+		Text:              fmt.Sprintf(ti.pageNumber, pageNum, pages),
+		Style:             style,
+		IgnoreInlineStyle: true,
+	}
+}
+
+// pageNumberSize page number footer size, page count doesn't change its height
+func (ti *TableImage) pageNumberSize(table *Table) image.Point {
+	cell := ti.pageNumberCell(table, 1, 1)
+	if cell == nil {
+		return image.ZP
+	}
+	return cell.Size()
+}
+
+// rowSegments split rows from start into ranges which are not crossed by row spans
+func (r Table) rowSegments(start int) [][2]int {
+	var (
+		segments [][2]int
+		end      = start
+	)
+	for rowIdx := start; rowIdx < len(r.rows); rowIdx++ {
+		for _, cell := range r.rows[rowIdx].Cells {
+			if spanEnd := rowIdx + cell.Span().Y; spanEnd > end {
+				end = spanEnd
+			}
+		}
+		if rowIdx+1 >= end {
+			segments = append(segments, [2]int{start, rowIdx + 1})
+			start = rowIdx + 1
+			end = start
+		}
+	}
+	return segments
+}
+
+// page create a page table with header rows [0, headers) and rows [start, end)
+func (r Table) page(headers int, start int, end int, withCaption bool, withFooter bool) *Table {
+	page := &Table{
+		colsWidth: r.colsWidth,
+	}
+	for _, section := range [][2]int{{0, headers}, {start, end}} {
+		for rowIdx := section[0]; rowIdx < section[1]; rowIdx++ {
+			row := r.rows[rowIdx]
+			cells := make([]Cell, 0, len(row.Cells))
+			for _, cell := range row.Cells {
+				if rowIdx+cell.RowSpan > section[1] {
+					cell.RowSpan = section[1] - rowIdx
+				}
+				cells = append(cells, cell)
+			}
+			row.Cells = cells
+			page.rows = append(page.rows, row)
+			page.rowsHeight = append(page.rowsHeight, r.rowsHeight[rowIdx])
+			page.cols = append(page.cols, r.cols[rowIdx])
+		}
+	}
+	if withCaption {
+		page.caption = r.caption
+		page.captionSize = r.captionSize
+	}
+	if withFooter {
+		page.footer = r.footer
+		page.footerSize = r.footerSize
+	}
+	return page
+}
+
+func sumInts(values []int) int {
+	var sum int
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
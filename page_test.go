@@ -0,0 +1,140 @@
+package tableimage
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// textRows rows with a single cell of each text
+func textRows(texts ...string) []Row {
+	rows := make([]Row, 0, len(texts))
+	for _, text := range texts {
+		rows = append(rows, Row{Cells: []Cell{{Text: text}}})
+	}
+	return rows
+}
+
+// pageTexts first cell text of each page row
+func pageTexts(pages []*Table) [][]string {
+	texts := make([][]string, 0, len(pages))
+	for _, page := range pages {
+		var rows []string
+		for _, row := range page.Rows() {
+			rows = append(rows, row.Cells[0].Text)
+		}
+		texts = append(texts, rows)
+	}
+	return texts
+}
+
+func TestPaginateHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		rows    []Row
+		headers int
+		want    [][]string
+	}{
+		{
+			name:    "header row",
+			rows:    append([]Row{{Header: true, Cells: []Cell{{Text: "h"}}}}, textRows("a", "b", "c", "d", "e", "f", "g")...),
+			headers: 1,
+			want:    [][]string{{"h", "a", "b", "c"}, {"h", "d", "e", "f"}, {"h", "g"}},
+		},
+		{
+			name:    "header rows option",
+			options: []Option{WithHeaderRows(2)},
+			rows:    textRows("h1", "h2", "a", "b", "c", "d"),
+			headers: 2,
+			want:    [][]string{{"h1", "h2", "a", "b", "c"}, {"h1", "h2", "d"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestTableImage(t, tt.options...)
+			table, err := NewTable(ti, tt.rows, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// header rows and 3 rows fit a page
+			maxHeight := ti.BorderSize().Y + sumInts(table.rowsHeight[:tt.headers+3])
+			pages := ti.Paginate(table, maxHeight)
+			if got := pageTexts(pages); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("pages = %q, want %q", got, tt.want)
+			}
+			for idx, page := range pages {
+				if height := ti.Size(page).Y; height > maxHeight {
+					t.Errorf("page %d height = %d, taller than %d", idx, height, maxHeight)
+				}
+			}
+		})
+	}
+}
+
+func TestPaginateRowSpan(t *testing.T) {
+	ti := newTestTableImage(t)
+	rows := textRows("a", "b", "c", "d", "e", "f")
+	rows[2].Cells = append(rows[2].Cells, Cell{Text: "span", RowSpan: 2})
+	table, err := NewTable(ti, rows, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxHeight := ti.BorderSize().Y + sumInts(table.rowsHeight[:3])
+	pages := ti.Paginate(table, maxHeight)
+	want := [][]string{{"a", "b"}, {"c", "d", "e"}, {"f"}}
+	if got := pageTexts(pages); !reflect.DeepEqual(got, want) {
+		t.Fatalf("pages = %q, want %q", got, want)
+	}
+	if span := pages[1].Rows()[0].Cells[1]; span.Text != "span" || span.RowSpan != 2 {
+		t.Errorf("spanned cell = %q with row span %d, want span with row span 2", span.Text, span.RowSpan)
+	}
+
+	// a row span taller than a page gets a page of its own
+	maxHeight = ti.BorderSize().Y + sumInts(table.rowsHeight[:1])
+	pages = ti.Paginate(table, maxHeight)
+	want = [][]string{{"a"}, {"b"}, {"c", "d"}, {"e"}, {"f"}}
+	if got := pageTexts(pages); !reflect.DeepEqual(got, want) {
+		t.Fatalf("pages = %q, want %q", got, want)
+	}
+}
+
+func TestPageNumber(t *testing.T) {
+	ti := newTestTableImage(t, WithPageNumber("page %d of %d"))
+	rows := textRows("a", "b", "c", "d", "e")
+	table, err := NewTable(ti, rows, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	numberHeight := ti.pageNumberSize(table).Y
+	if numberHeight <= 0 {
+		t.Fatalf("page number height = %d, want positive", numberHeight)
+	}
+	maxHeight := ti.BorderSize().Y + numberHeight + sumInts(table.rowsHeight[:2])
+	pages := ti.Paginate(table, maxHeight)
+	if len(pages) != 3 {
+		t.Fatalf("got %d pages, want 3", len(pages))
+	}
+	for idx, page := range pages {
+		want := fmt.Sprintf("page %d of %d", idx+1, len(pages))
+		if got := ti.pageNumberCell(page, idx+1, len(pages)).Text; got != want {
+			t.Errorf("page %d number = %q, want %q", idx, got, want)
+		}
+	}
+	imgs, err := ti.DrawPages(rows, nil, nil, maxHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imgs) != len(pages) {
+		t.Fatalf("drew %d pages, want %d", len(imgs), len(pages))
+	}
+	for idx, img := range imgs {
+		if got, want := img.Bounds().Dy(), ti.Size(pages[idx]).Y+numberHeight; got != want {
+			t.Errorf("page %d image height = %d, want %d", idx, got, want)
+		}
+	}
+
+	if cell := newTestTableImage(t).pageNumberCell(table, 1, 1); cell != nil {
+		t.Errorf("page number cell = %q without WithPageNumber, want nil", cell.Text)
+	}
+}
@@ -0,0 +1,198 @@
+package tableimage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/golang/freetype/truetype"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// fontLoader loads truetype font file content of font
+type fontLoader func(font *Font) ([]byte, error)
+
+// pdfRenderer renders table as a single page pdf document, one pixel maps to one point
+type pdfRenderer struct {
+	pdf        *gofpdf.Fpdf
+	loadFont   fontLoader
+	fonts      map[*truetype.Font]string
+	imageCount int
+	alpha      uint8
+	clips      []uint8
+	err        error
+}
+
+func newPDFRenderer(size image.Point, loadFont fontLoader) *pdfRenderer {
+	pageSize := gofpdf.SizeType{Wd: float64(size.X), Ht: float64(size.Y)}
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr: "pt",
+		Size:    pageSize,
+	})
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPageFormat("P", pageSize)
+	return &pdfRenderer{
+		pdf:      pdf,
+		loadFont: loadFont,
+		fonts:    make(map[*truetype.Font]string),
+		alpha:    255,
+	}
+}
+
+// FillRect implement Renderer
+func (r *pdfRenderer) FillRect(bounds image.Rectangle, color string) {
+	c := ColorFromHex(color)
+	if c.A == 0 {
+		return
+	}
+	r.setAlpha(c.A)
+	r.pdf.SetFillColor(int(c.R), int(c.G), int(c.B))
+	r.pdf.Rect(float64(bounds.Min.X), float64(bounds.Min.Y), float64(bounds.Dx()), float64(bounds.Dy()), "F")
+}
+
+// StrokeLine implement Renderer
+func (r *pdfRenderer) StrokeLine(from image.Point, to image.Point, color string, width int) {
+	c := ColorFromHex(color)
+	if width == 0 || c.A == 0 {
+		return
+	}
+	r.setAlpha(c.A)
+	r.pdf.SetDrawColor(int(c.R), int(c.G), int(c.B))
+	r.pdf.SetLineWidth(float64(width))
+	r.pdf.Line(float64(from.X), float64(from.Y), float64(to.X), float64(to.Y))
+}
+
+// DrawGlyphRun implement Renderer
+func (r *pdfRenderer) DrawGlyphRun(pt image.Point, run GlyphRun) {
+	font := run.Font
+	c := ColorFromHex(run.Color)
+	if font == nil || font.Font == nil || run.Text == "" || c.A == 0 {
+		return
+	}
+	family, err := r.fontFamily(font)
+	if err != nil {
+		r.setError(err)
+		return
+	}
+	r.setAlpha(c.A)
+	r.pdf.SetFont(family, "", font.Size)
+	r.pdf.SetTextColor(int(c.R), int(c.G), int(c.B))
+	r.pdf.Text(float64(pt.X), float64(pt.Y)+font.Size, run.Text)
+}
+
+// DrawImage implement Renderer
+func (r *pdfRenderer) DrawImage(img *Image, pt image.Point) {
+	if img == nil || img.Data == nil {
+		return
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img.Data); err != nil {
+		r.setError(err)
+		return
+	}
+	r.imageCount++
+	name := fmt.Sprintf("image%d", r.imageCount)
+	opts := gofpdf.ImageOptions{ImageType: "PNG"}
+	r.pdf.RegisterImageOptionsReader(name, opts, &buf)
+	bounds := img.Data.Bounds()
+	scale := img.Scale()
+	width := float64(bounds.Dx()) * scale
+	height := float64(bounds.Dy()) * scale
+	r.pdf.ImageOptions(name, float64(pt.X), float64(pt.Y), width, height, false, opts, 0, "")
+}
+
+// PushClip implement Renderer
+func (r *pdfRenderer) PushClip(bounds image.Rectangle) {
+	r.clips = append(r.clips, r.alpha)
+	r.pdf.ClipRect(float64(bounds.Min.X), float64(bounds.Min.Y), float64(bounds.Dx()), float64(bounds.Dy()), false)
+}
+
+// PopClip implement Renderer, ending the clip restores graphics state saved by PushClip
+func (r *pdfRenderer) PopClip() {
+	if len(r.clips) == 0 {
+		return
+	}
+	r.alpha = r.clips[len(r.clips)-1]
+	r.clips = r.clips[:len(r.clips)-1]
+	r.pdf.ClipEnd()
+}
+
+// fontFamily register font as an utf8 font, gofpdf embeds the subset of used glyphs
+func (r *pdfRenderer) fontFamily(font *Font) (string, error) {
+	if family, found := r.fonts[font.Font]; found {
+		return family, nil
+	}
+	if r.loadFont == nil {
+		return "", errors.New("missing font loader")
+	}
+	bs, err := r.loadFont(font)
+	if err != nil {
+		return "", err
+	}
+	family := fmt.Sprintf("font%d", len(r.fonts)+1)
+	r.pdf.AddUTF8FontFromBytes(family, "", bs)
+	r.fonts[font.Font] = family
+	return family, nil
+}
+
+func (r *pdfRenderer) setAlpha(alpha uint8) {
+	if r.alpha == alpha {
+		return
+	}
+	r.alpha = alpha
+	r.pdf.SetAlpha(float64(alpha)/255, "Normal")
+}
+
+func (r *pdfRenderer) setError(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// WriteTo write pdf document to io.Writer
+func (r *pdfRenderer) WriteTo(w io.Writer) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	var buf bytes.Buffer
+	if err := r.pdf.Output(&buf); err != nil {
+		return 0, err
+	}
+	return buf.WriteTo(w)
+}
+
+// writePDFImage wraps a raster image into a pdf document
+func writePDFImage(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	r := newPDFRenderer(bounds.Size(), nil)
+	r.DrawImage(&Image{Data: img, Size: bounds.Size()}, image.ZP)
+	_, err := r.WriteTo(w)
+	return err
+}
+
+// pdfFontFile truetype font file content of font for pdf output, from WithFontFile, a FontFileCache or the font folder
+func (ti *TableImage) pdfFontFile(font *Font) ([]byte, error) {
+	if file, found := ti.fontFiles[font.Font]; found {
+		return file, nil
+	}
+	if font.Data == nil {
+		return nil, errors.New("pdf output requires font data or a font set by WithFontFile")
+	}
+	if cache, ok := ti.fontCache.(FontFileCache); ok {
+		return cache.LoadFile(*font.Data)
+	}
+	if ti.fontFolder != "" {
+		name, err := fontFileName(*font.Data)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.ReadFile(filepath.Join(ti.fontFolder, name))
+	}
+	return nil, errors.New("pdf output requires a FontFileCache, font folder or a font set by WithFontFile")
+}
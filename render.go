@@ -0,0 +1,132 @@
+package tableimage
+
+import (
+	"image"
+)
+
+// Renderer draws table primitives to an output backend
+type Renderer interface {
+	// FillRect fill bounds with color
+	FillRect(bounds image.Rectangle, color string)
+	// StrokeLine stroke a line between two points
+	StrokeLine(from image.Point, to image.Point, color string, width int)
+	// DrawGlyphRun draw a run of text, pt is the top left point of the line box
+	DrawGlyphRun(pt image.Point, run GlyphRun)
+	// DrawImage draw an image at pt, scaled to img.Size
+	DrawImage(img *Image, pt image.Point)
+	// PushClip restrict drawing to bounds intersected with current clip
+	PushClip(bounds image.Rectangle)
+	// PopClip restore the clip before last PushClip
+	PopClip()
+}
+
+// CellRenderer a Renderer told where table rows and cells begin and end, so that structured outputs like svg
+// can tag header cells
+type CellRenderer interface {
+	Renderer
+	// BeginRow start a table row, header is set for header rows
+	BeginRow(header bool)
+	// EndRow end the row started by last BeginRow
+	EndRow()
+	// BeginCell start a cell of current row
+	BeginCell()
+	// EndCell end the cell started by last BeginCell
+	EndCell()
+}
+
+// GlyphRun a run of text sharing the same font and color
+type GlyphRun struct {
+	// Text run content
+	Text string
+	// Width measured run width
+	Width int
+	// Color text color
+	Color string
+	// Font run font
+	Font *Font
+}
+
+// RenderOpType recorded render operation type
+type RenderOpType int
+
+const (
+	// FillRectOp FillRect operation
+	FillRectOp RenderOpType = iota
+	// StrokeLineOp StrokeLine operation
+	StrokeLineOp
+	// GlyphRunOp DrawGlyphRun operation
+	GlyphRunOp
+	// ImageOp DrawImage operation
+	ImageOp
+	// PushClipOp PushClip operation
+	PushClipOp
+	// PopClipOp PopClip operation
+	PopClipOp
+)
+
+// RenderOp a recorded render operation
+type RenderOp struct {
+	// Type operation type
+	Type RenderOpType
+	// Bounds FillRect/PushClip bounds
+	Bounds image.Rectangle
+	// From StrokeLine start point, DrawGlyphRun/DrawImage point
+	From image.Point
+	// To StrokeLine end point
+	To image.Point
+	// Color FillRect/StrokeLine color
+	Color string
+	// Width StrokeLine width
+	Width int
+	// Run DrawGlyphRun text run
+	Run GlyphRun
+	// Image DrawImage image
+	Image *Image
+}
+
+// RecordingRenderer a Renderer records operations instead of drawing, useful for testing layouts
+type RecordingRenderer struct {
+	// Ops recorded operations
+	Ops []RenderOp
+}
+
+// FillRect implement Renderer
+func (r *RecordingRenderer) FillRect(bounds image.Rectangle, color string) {
+	r.Ops = append(r.Ops, RenderOp{Type: FillRectOp, Bounds: bounds, Color: color})
+}
+
+// StrokeLine implement Renderer
+func (r *RecordingRenderer) StrokeLine(from image.Point, to image.Point, color string, width int) {
+	r.Ops = append(r.Ops, RenderOp{Type: StrokeLineOp, From: from, To: to, Color: color, Width: width})
+}
+
+// DrawGlyphRun implement Renderer
+func (r *RecordingRenderer) DrawGlyphRun(pt image.Point, run GlyphRun) {
+	r.Ops = append(r.Ops, RenderOp{Type: GlyphRunOp, From: pt, Run: run})
+}
+
+// DrawImage implement Renderer
+func (r *RecordingRenderer) DrawImage(img *Image, pt image.Point) {
+	r.Ops = append(r.Ops, RenderOp{Type: ImageOp, From: pt, Image: img})
+}
+
+// PushClip implement Renderer
+func (r *RecordingRenderer) PushClip(bounds image.Rectangle) {
+	r.Ops = append(r.Ops, RenderOp{Type: PushClipOp, Bounds: bounds})
+}
+
+// PopClip implement Renderer
+func (r *RecordingRenderer) PopClip() {
+	r.Ops = append(r.Ops, RenderOp{Type: PopClipOp})
+}
+
+// Texts returns text of recorded glyph runs in drawing order
+func (r *RecordingRenderer) Texts() []string {
+	var texts []string
+	for _, op := range r.Ops {
+		if op.Type == GlyphRunOp {
+			texts = append(texts, op.Run.Text)
+		}
+	}
+	return texts
+}
@@ -0,0 +1,259 @@
+package tableimage
+
+import (
+	"image"
+	"testing"
+
+	"github.com/llgcode/draw2d"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// newTestTableImage TableImage with Go fonts, bold and italic variants are loaded from an in memory font cache
+func newTestTableImage(t *testing.T, options ...Option) *TableImage {
+	t.Helper()
+	data := draw2d.FontData{Name: "Go", Family: draw2d.FontFamilySans}
+	cache := NewFolderFontCache("")
+	for style, file := range map[draw2d.FontStyle][]byte{
+		draw2d.FontStyleNormal: goregular.TTF,
+		draw2d.FontStyleBold:   gobold.TTF,
+		draw2d.FontStyleItalic: goitalic.TTF,
+	} {
+		variant := data
+		variant.Style = style
+		if err := cache.StoreFile(variant, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	options = append([]Option{WithFontCache(cache), WithFontData(&data)}, options...)
+	ti, err := New(options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ti
+}
+
+// record lay out rows and record render operations
+func record(t *testing.T, ti *TableImage, rows []Row) (*Table, *RecordingRenderer) {
+	t.Helper()
+	table, err := NewTable(ti, rows, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &RecordingRenderer{}
+	ti.Render(r, table)
+	return table, r
+}
+
+// findOps recorded operations of type matching fn
+func findOps(r *RecordingRenderer, opType RenderOpType, fn func(op RenderOp) bool) []RenderOp {
+	var ops []RenderOp
+	for _, op := range r.Ops {
+		if op.Type == opType && fn(op) {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// fillBounds bounds of the only FillRect with color
+func fillBounds(t *testing.T, r *RecordingRenderer, color string) image.Rectangle {
+	t.Helper()
+	ops := findOps(r, FillRectOp, func(op RenderOp) bool { return op.Color == color })
+	if len(ops) != 1 {
+		t.Fatalf("FillRect %s recorded %d times, want 1", color, len(ops))
+	}
+	return ops[0].Bounds
+}
+
+// glyphRun the only glyph run of text
+func glyphRun(t *testing.T, r *RecordingRenderer, text string) RenderOp {
+	t.Helper()
+	ops := findOps(r, GlyphRunOp, func(op RenderOp) bool { return op.Run.Text == text })
+	if len(ops) != 1 {
+		t.Fatalf("glyph run %q recorded %d times, want 1", text, len(ops))
+	}
+	return ops[0]
+}
+
+func TestRenderSpans(t *testing.T) {
+	ti := newTestTableImage(t)
+	rows := []Row{
+		{Cells: []Cell{{Text: "span", ColSpan: 2, Style: &Style{BgColor: "#ff0000"}}, {Text: "tall", RowSpan: 2, Style: &Style{BgColor: "#00ff00"}}}},
+		{Cells: []Cell{{Text: "left", Style: &Style{BgColor: "#0000ff"}}, {Text: "a much wider cell", Style: &Style{BgColor: "#ffff00"}}}},
+	}
+	table, r := record(t, ti, rows)
+	span := fillBounds(t, r, "#ff0000")
+	tall := fillBounds(t, r, "#00ff00")
+	left := fillBounds(t, r, "#0000ff")
+	wide := fillBounds(t, r, "#ffff00")
+	if span.Min.X != left.Min.X || span.Max.X != wide.Max.X {
+		t.Errorf("col span bounds %v, want x from %d to %d", span, left.Min.X, wide.Max.X)
+	}
+	if tall.Min.Y != span.Min.Y || tall.Max.Y != wide.Max.Y {
+		t.Errorf("row span bounds %v, want y from %d to %d", tall, span.Min.Y, wide.Max.Y)
+	}
+	if tall.Min.X != wide.Max.X {
+		t.Errorf("row span starts at x %d, want %d", tall.Min.X, wide.Max.X)
+	}
+	if got, want := span.Size(), table.CellBounds(0, 0).Size(); got != want {
+		t.Errorf("col span size %v, CellBounds size %v", got, want)
+	}
+	for text, bounds := range map[string]image.Rectangle{"span": span, "tall": tall, "left": left} {
+		if pt := glyphRun(t, r, text).From; !pt.In(bounds) {
+			t.Errorf("glyph run %q at %v outside of cell %v", text, pt, bounds)
+		}
+	}
+}
+
+func TestRenderBorders(t *testing.T) {
+	ti := newTestTableImage(t)
+	border := &Border{
+		Top:    Line{Color: "#ff0000", Width: 1},
+		Right:  Line{Color: "#00ff00", Width: 2},
+		Bottom: Line{Color: "#0000ff", Width: 3},
+		Left:   Line{Color: "#ffff00", Width: 4},
+	}
+	_, r := record(t, ti, []Row{{Cells: []Cell{{Text: "border", Style: &Style{BgColor: "#123456", Border: border}}}}})
+	bounds := fillBounds(t, r, "#123456")
+	tests := []struct {
+		line Line
+		from image.Point
+		to   image.Point
+	}{
+		{border.Top, bounds.Min, image.Pt(bounds.Max.X, bounds.Min.Y)},
+		{border.Right, image.Pt(bounds.Max.X, bounds.Min.Y), bounds.Max},
+		{border.Bottom, image.Pt(bounds.Min.X, bounds.Max.Y), bounds.Max},
+		{border.Left, bounds.Min, image.Pt(bounds.Min.X, bounds.Max.Y)},
+	}
+	for _, tt := range tests {
+		ops := findOps(r, StrokeLineOp, func(op RenderOp) bool { return op.Color == tt.line.Color })
+		if len(ops) != 1 {
+			t.Errorf("StrokeLine %s recorded %d times, want 1", tt.line.Color, len(ops))
+			continue
+		}
+		if op := ops[0]; op.From != tt.from || op.To != tt.to || op.Width != tt.line.Width {
+			t.Errorf("StrokeLine %s from %v to %v width %d, want from %v to %v width %d", tt.line.Color, op.From, op.To, op.Width, tt.from, tt.to, tt.line.Width)
+		}
+	}
+}
+
+func TestRenderAlignment(t *testing.T) {
+	ti := newTestTableImage(t)
+	rows := []Row{
+		{Cells: []Cell{{Text: "a cell making the column wide"}}},
+		{Cells: []Cell{{Text: "left", Style: &Style{Align: LEFT, BgColor: "#000001"}}}},
+		{Cells: []Cell{{Text: "center", Style: &Style{Align: CENTER, BgColor: "#000002"}}}},
+		{Cells: []Cell{{Text: "right", Style: &Style{Align: RIGHT, BgColor: "#000003"}}}},
+	}
+	table, r := record(t, ti, rows)
+	tests := []struct {
+		row   int
+		text  string
+		color string
+		x     func(inner image.Rectangle, width int) int
+	}{
+		{1, "left", "#000001", func(inner image.Rectangle, width int) int { return inner.Min.X }},
+		{2, "center", "#000002", func(inner image.Rectangle, width int) int { return inner.Min.X + (inner.Dx()-width)/2 }},
+		{3, "right", "#000003", func(inner image.Rectangle, width int) int { return inner.Max.X - width }},
+	}
+	for _, tt := range tests {
+		inner := table.Rows()[tt.row].Cells[0].InnerBounds(fillBounds(t, r, tt.color))
+		op := glyphRun(t, r, tt.text)
+		if want := tt.x(inner, op.Run.Width); op.From.X != want {
+			t.Errorf("%s aligned glyph run at x %d, want %d", tt.text, op.From.X, want)
+		}
+	}
+}
+
+func TestRenderDecimal(t *testing.T) {
+	ti := newTestTableImage(t, WithTableWidth(200))
+	decimal := func(color string) *Style {
+		return &Style{Align: DECIMAL, BgColor: color}
+	}
+	rows := []Row{
+		{Cells: []Cell{{Text: "1.5 kg", Style: decimal("#000001")}}},
+		{Cells: []Cell{{Text: "2.25", Style: decimal("#000002")}}},
+		{Cells: []Cell{{Text: "1234", Style: decimal("#000003")}}},
+	}
+	table, r := record(t, ti, rows)
+	faces := make(faceCache)
+	var separator int
+	for idx, tt := range []struct {
+		text   string
+		before string
+		color  string
+	}{
+		{"1.5 kg", "1", "#000001"},
+		{"2.25", "2", "#000002"},
+		{"1234", "1234", "#000003"},
+	} {
+		cell := table.Rows()[idx].Cells[0]
+		inner := cell.InnerBounds(fillBounds(t, r, tt.color))
+		op := glyphRun(t, r, tt.text)
+		if right := op.From.X + op.Run.Width; right > inner.Max.X {
+			t.Errorf("%q ends at x %d, right of cell content %d", tt.text, right, inner.Max.X)
+		}
+		x := op.From.X + int(stringWidth(tt.before, faces.face(cell.Style.Font)))
+		if idx == 0 {
+			separator = x
+		} else if x != separator {
+			t.Errorf("%q separator at x %d, want %d", tt.text, x, separator)
+		}
+	}
+}
+
+func TestRenderDecimalImageOffset(t *testing.T) {
+	ti := newTestTableImage(t)
+	faces := make(faceCache)
+	maxWidth := int(stringWidth("12.5 kg", faces.face(ti.style.Font))) + 10
+	icon := &Image{Data: image.NewRGBA(image.Rect(0, 0, 40, 10)), Size: image.Pt(40, 10), Align: LEFT}
+	rows := []Row{
+		{Cells: []Cell{{Text: "12.5 kg", Image: icon, Style: &Style{Align: DECIMAL, MaxWidth: maxWidth}}}},
+		{Cells: []Cell{{Text: "3.75", Style: &Style{Align: DECIMAL, MaxWidth: maxWidth, BgColor: "#000001"}}}},
+	}
+	table, r := record(t, ti, rows)
+	glyphRun(t, r, "kg")
+	inner := table.Rows()[1].Cells[0].InnerBounds(fillBounds(t, r, "#000001"))
+	// the widest fraction is drawn flush right, text wrapped next to the image doesn't widen it
+	if op := glyphRun(t, r, "3.75"); op.From.X+op.Run.Width != inner.Max.X {
+		t.Errorf("3.75 ends at x %d, want %d", op.From.X+op.Run.Width, inner.Max.X)
+	}
+}
+
+func TestRenderDataBars(t *testing.T) {
+	ti := newTestTableImage(t)
+	max := 20.0
+	bar := func(value float64, color string, max *float64) *Chart {
+		return &Chart{Type: DataBar, Value: value, Color: color, Size: image.Pt(90, 10), Max: max}
+	}
+	rows := []Row{
+		{Cells: []Cell{{Text: "a", Chart: bar(10, "#000001", nil)}, {Text: "max", Chart: bar(10, "#000004", &max)}}},
+		{Cells: []Cell{{Text: "b", Chart: bar(5, "#000002", nil)}}},
+		{Cells: []Cell{{Text: "c", Chart: bar(-5, "#000003", nil)}}},
+	}
+	_, r := record(t, ti, rows)
+	full := fillBounds(t, r, "#000001")
+	// column range is -5 to 10, zero is a third of the chart width from its left edge
+	left := full.Max.X - 90
+	zero := left + 30
+	tests := []struct {
+		color string
+		minX  int
+		maxX  int
+	}{
+		{"#000001", zero, left + 90},
+		{"#000002", zero, zero + 30},
+		{"#000003", left, zero},
+	}
+	for _, tt := range tests {
+		if bounds := fillBounds(t, r, tt.color); bounds.Min.X != tt.minX || bounds.Max.X != tt.maxX {
+			t.Errorf("bar %s from x %d to %d, want from %d to %d", tt.color, bounds.Min.X, bounds.Max.X, tt.minX, tt.maxX)
+		}
+	}
+	// explicit max is kept, the own column has no negative bar
+	if bounds := fillBounds(t, r, "#000004"); bounds.Dx() != 45 {
+		t.Errorf("bar with max 20 width %d, want 45", bounds.Dx())
+	}
+}
@@ -0,0 +1,217 @@
+package tableimage
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Rule conditional formatting rule, returns style for a cell or nil if the rule doesn't match
+// rowIdx is the index of the row among non header rows
+type Rule interface {
+	Apply(rowIdx int, colIdx int, cell Cell) *Style
+}
+
+// RuleFunc function implements Rule
+type RuleFunc func(rowIdx int, colIdx int, cell Cell) *Style
+
+// Apply implement Rule
+func (fn RuleFunc) Apply(rowIdx int, colIdx int, cell Cell) *Style {
+	return fn(rowIdx, colIdx, cell)
+}
+
+// StripeRule zebra striping, styles every other group of Every rows starting from the second group
+type StripeRule struct {
+	// Every rows count in a stripe, defaults to 1
+	Every int `json:"every,omitempty"`
+	// Style stripe style
+	Style *Style `json:"style,omitempty"`
+}
+
+// Apply implement Rule
+func (r StripeRule) Apply(rowIdx int, colIdx int, cell Cell) *Style {
+	every := r.Every
+	if every < 1 {
+		every = 1
+	}
+	if (rowIdx/every)%2 == 1 {
+		return r.Style
+	}
+	return nil
+}
+
+// CompareOp numeric comparison operator
+type CompareOp string
+
+const (
+	// LessThan <
+	LessThan CompareOp = "<"
+	// LessOrEqual <=
+	LessOrEqual CompareOp = "<="
+	// Equal =
+	Equal CompareOp = "="
+	// NotEqual !=
+	NotEqual CompareOp = "!="
+	// GreaterOrEqual >=
+	GreaterOrEqual CompareOp = ">="
+	// GreaterThan >
+	GreaterThan CompareOp = ">"
+)
+
+// Compare compare a with b
+func (op CompareOp) Compare(a float64, b float64) bool {
+	switch op {
+	case LessThan:
+		return a < b
+	case LessOrEqual:
+		return a <= b
+	case Equal:
+		return a == b
+	case NotEqual:
+		return a != b
+	case GreaterOrEqual:
+		return a >= b
+	case GreaterThan:
+		return a > b
+	}
+	return false
+}
+
+// NumericRule styles cells whose text is a number matching Op against Value, e.g. {Op: LessThan, Value: 0} for negative numbers
+type NumericRule struct {
+	// Op comparison operator
+	Op CompareOp `json:"op,omitempty"`
+	// Value compared value
+	Value float64 `json:"value,omitempty"`
+	// Columns limit rule to columns, all columns if empty
+	Columns []int `json:"columns,omitempty"`
+	// Style matched cell style
+	Style *Style `json:"style,omitempty"`
+}
+
+// Apply implement Rule
+func (r NumericRule) Apply(rowIdx int, colIdx int, cell Cell) *Style {
+	if !inColumns(colIdx, r.Columns) {
+		return nil
+	}
+	v, ok := cell.numericValue()
+	if !ok || !r.Op.Compare(v, r.Value) {
+		return nil
+	}
+	return r.Style
+}
+
+// ruleStyle merge matched rules styles for a cell, later rules take precedence
+func (ti *TableImage) ruleStyle(rowIdx int, colIdx int, cell Cell) *Style {
+	var style *Style
+	for idx := len(ti.rules) - 1; idx >= 0; idx-- {
+		matched := ti.rules[idx].Apply(rowIdx, colIdx, cell)
+		if matched == nil {
+			continue
+		}
+		if style == nil {
+			copied := *matched
+			style = &copied
+			continue
+		}
+		style.Inherit(matched, ti.fontCache)
+	}
+	return style
+}
+
+// applyRules apply rules style to cell before it inherits row and column styles
+func (ti *TableImage) applyRules(rowIdx int, colIdx int, cell Cell) Cell {
+	return ti.mergeCellStyle(cell, ti.ruleStyle(rowIdx, colIdx, cell))
+}
+
+// mergeCellStyle set style as cell style, cell explicit style takes precedence
+func (ti *TableImage) mergeCellStyle(cell Cell, style *Style) Cell {
+	if style == nil {
+		return cell
+	}
+	if cell.Style != nil {
+		explicit := *cell.Style
+		explicit.Inherit(style, ti.fontCache)
+		style = &explicit
+	}
+	cell.Style = style
+	return cell
+}
+
+func inColumns(colIdx int, columns []int) bool {
+	if len(columns) == 0 {
+		return true
+	}
+	for _, c := range columns {
+		if c == colIdx {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNumber parse numeric cell text with english separators, see parseLocaleNumber
+func parseNumber(s string) (float64, bool) {
+	return parseLocaleNumber(s, NumberLocales["en"])
+}
+
+// parseLocaleNumber parse numeric cell text with locale decimal and thousands separators, ignores currency symbols
+// and percent sign. Thousands separators, spaces and underscores group integer digits by three, other groupings like
+// "1,5" in english are ambiguous and aren't numbers. Only a sign, digits and one decimal separator are accepted,
+// exponents, hex, NaN and Inf forms aren't numbers
+func parseLocaleNumber(s string, locale NumberLocale) (float64, bool) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSpace(strings.TrimRight(s, "%"))
+	neg := strings.HasPrefix(s, "-")
+	if neg || strings.HasPrefix(s, "+") {
+		s = strings.TrimSpace(s[1:])
+	}
+	s = strings.TrimLeft(s, "$€£¥")
+	s = strings.TrimSpace(strings.TrimRight(s, "$€£¥"))
+	if s == "" {
+		return 0, false
+	}
+	integer, fraction := s, ""
+	if idx := strings.Index(s, locale.Decimal); locale.Decimal != "" && idx >= 0 {
+		integer, fraction = s[:idx], s[idx+len(locale.Decimal):]
+	}
+	integer, ok := groupedDigits(integer, locale)
+	if !ok || !isDigits(integer) || !isDigits(fraction) || integer+fraction == "" {
+		return 0, false
+	}
+	if fraction != "" {
+		integer += "." + fraction
+	}
+	v, err := strconv.ParseFloat(integer, 64)
+	if err != nil {
+		return 0, false
+	}
+	if neg {
+		v = -v
+	}
+	return v, true
+}
+
+// groupedDigits remove separators of integer digits, false if a group between separators isn't three digits
+func groupedDigits(s string, locale NumberLocale) (string, bool) {
+	var separators []string
+	for _, sep := range []string{locale.Thousands, " ", "\u00a0", "\u202f", "_"} {
+		if sep != "" && sep != locale.Decimal {
+			separators = append(separators, sep, "\x00")
+		}
+	}
+	groups := strings.Split(strings.NewReplacer(separators...).Replace(s), "\x00")
+	if len(groups) == 1 {
+		return s, true
+	}
+	for idx, group := range groups {
+		if group == "" || len(group) > 3 || (idx > 0 && len(group) != 3) || !isDigits(group) {
+			return "", false
+		}
+	}
+	return strings.Join(groups, ""), true
+}
+
+// isDigits check if s has ascii digits only
+func isDigits(s string) bool {
+	return strings.Trim(s, "0123456789") == ""
+}
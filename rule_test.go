@@ -0,0 +1,71 @@
+package tableimage
+
+import "testing"
+
+func TestParseLocaleNumber(t *testing.T) {
+	tests := []struct {
+		s      string
+		locale string
+		want   float64
+		ok     bool
+	}{
+		{"42", "en", 42, true},
+		{" -1,234.5 ", "en", -1234.5, true},
+		{"$1,234,567", "en", 1234567, true},
+		{"12.5%", "en", 12.5, true},
+		{"1 000", "en", 1000, true},
+		{"1_000_000", "en", 1000000, true},
+		{".5", "en", 0.5, true},
+		{"+7", "en", 7, true},
+		{"1e3", "en", 0, false},
+		{"NaN", "en", 0, false},
+		{"nan", "en", 0, false},
+		{"Inf", "en", 0, false},
+		{"+Inf", "en", 0, false},
+		{"-Infinity", "en", 0, false},
+		{"0x1p-2", "en", 0, false},
+		{"0x10", "en", 0, false},
+		{"1.2.3", "en", 0, false},
+		{"--5", "en", 0, false},
+		{".", "en", 0, false},
+		{"1,5", "en", 0, false},
+		{"12,34", "en", 0, false},
+		{"1,2345", "en", 0, false},
+		{",123", "en", 0, false},
+		{"1.234,5", "en", 0, false},
+		{"1.234,5", "de", 1234.5, true},
+		{"1,5", "de", 1.5, true},
+		{"-12,50 €", "de", -12.5, true},
+		{"1.5", "de", 0, false},
+		{"1\u00a0234,5", "fr", 1234.5, true},
+		{"1\u202f234,5", "fr", 1234.5, true},
+		{"1'234.5", "de-CH", 1234.5, true},
+		{"", "en", 0, false},
+		{"$", "en", 0, false},
+		{"abc", "en", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseLocaleNumber(tt.s, numberLocale(tt.locale))
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseLocaleNumber(%q, %s) = %v, %v, want %v, %v", tt.s, tt.locale, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCellNumericValueLocale(t *testing.T) {
+	tests := []struct {
+		cell Cell
+		want float64
+		ok   bool
+	}{
+		{Cell{Text: "1,5"}, 0, false},
+		{Cell{Text: "1,5", Format: &ValueFormat{Locale: "de"}}, 1.5, true},
+		{Cell{Text: "1.234,5", Format: &ValueFormat{Locale: "de-DE"}}, 1234.5, true},
+		{Cell{Text: "ignored", Value: 7}, 7, true},
+	}
+	for _, tt := range tests {
+		if got, ok := tt.cell.numericValue(); ok != tt.ok || got != tt.want {
+			t.Errorf("%+v numericValue = %v, %v, want %v, %v", tt.cell, got, ok, tt.want, tt.ok)
+		}
+	}
+}
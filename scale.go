@@ -0,0 +1,128 @@
+package tableimage
+
+import (
+	"math"
+)
+
+// ColorScale background colors interpolated by numeric cell values in a column, like spreadsheet color scales
+type ColorScale struct {
+	// MinColor color for the minimum value
+	MinColor string `json:"min_color,omitempty"`
+	// MidColor color for the middle value, makes a diverging scale
+	MidColor string `json:"mid_color,omitempty"`
+	// MaxColor color for the maximum value
+	MaxColor string `json:"max_color,omitempty"`
+	// Min fixed minimum value, column minimum by default
+	Min *float64 `json:"min,omitempty"`
+	// Mid fixed middle value of diverging scale, average of minimum and maximum by default
+	Mid *float64 `json:"mid,omitempty"`
+	// Max fixed maximum value, column maximum by default
+	Max *float64 `json:"max,omitempty"`
+}
+
+// LinearScale create color scale from minColor to maxColor
+func LinearScale(minColor string, maxColor string) *ColorScale {
+	return &ColorScale{
+		MinColor: minColor,
+		MaxColor: maxColor,
+	}
+}
+
+// DivergingScale create color scale from minColor through midColor at mid to maxColor
+func DivergingScale(minColor string, midColor string, maxColor string, mid float64) *ColorScale {
+	return &ColorScale{
+		MinColor: minColor,
+		MidColor: midColor,
+		MaxColor: maxColor,
+		Mid:      &mid,
+	}
+}
+
+// Style background and contrasting text color for value v in range [min, max]
+func (s ColorScale) Style(v float64, min float64, max float64) *Style {
+	if s.Min != nil {
+		min = *s.Min
+	}
+	if s.Max != nil {
+		max = *s.Max
+	}
+	var (
+		from = ColorFromHex(s.MinColor)
+		to   = ColorFromHex(s.MaxColor)
+		t    = scaleRatio(v, min, max)
+	)
+	if s.MidColor != "" {
+		mid := (min + max) / 2
+		if s.Mid != nil {
+			mid = *s.Mid
+		}
+		if v <= mid {
+			to = ColorFromHex(s.MidColor)
+			t = scaleRatio(v, min, mid)
+		} else {
+			from = ColorFromHex(s.MidColor)
+			t = scaleRatio(v, mid, max)
+		}
+	}
+	bgColor := interpolateColor(from, to, t)
+	return &Style{
+		BgColor: HexFromColor(bgColor),
+		Color:   contrastColor(bgColor),
+	}
+}
+
+func scaleRatio(v float64, min float64, max float64) float64 {
+	if max-min < 1e-15 {
+		return 0.5
+	}
+	return math.Max(0, math.Min(1, (v-min)/(max-min)))
+}
+
+// columnRanges numeric values range of non header cells in columns with color scale
+func (ti *TableImage) columnRanges(rows []Row, cols [][]int) map[int][2]float64 {
+	ranges := make(map[int][2]float64)
+	for rowIdx, row := range rows {
+		if row.Header {
+			continue
+		}
+		for cellIdx, cell := range row.Cells {
+			colIdx := cols[rowIdx][cellIdx]
+			if ti.colorScale(colIdx) == nil {
+				continue
+			}
+			v, ok := cell.numericValue()
+			if !ok {
+				continue
+			}
+			r, found := ranges[colIdx]
+			if !found {
+				r = [2]float64{v, v}
+			}
+			r[0] = math.Min(r[0], v)
+			r[1] = math.Max(r[1], v)
+			ranges[colIdx] = r
+		}
+	}
+	return ranges
+}
+
+func (ti *TableImage) colorScale(colIdx int) *ColorScale {
+	if colIdx >= len(ti.columns) {
+		return nil
+	}
+	return ti.columns[colIdx].ColorScale
+}
+
+// applyColorScale apply column color scale style to a numeric cell
+func (ti *TableImage) applyColorScale(colIdx int, cell Cell, ranges map[int][2]float64) Cell {
+	scale := ti.colorScale(colIdx)
+	r, found := ranges[colIdx]
+	if scale == nil || !found {
+		return cell
+	}
+	v, ok := cell.numericValue()
+	if !ok {
+		return cell
+	}
+	return ti.mergeCellStyle(cell, scale.Style(v, r[0], r[1]))
+}
@@ -0,0 +1,50 @@
+package tableimage
+
+import (
+	"reflect"
+	"testing"
+)
+
+// columnBgColors background color of the first cell of each row
+func columnBgColors(t *testing.T, ti *TableImage, rows []Row) []string {
+	t.Helper()
+	table, err := NewTable(ti, rows, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	colors := make([]string, 0, len(rows))
+	for _, row := range table.Rows() {
+		colors = append(colors, row.Cells[0].Style.BgColor)
+	}
+	return colors
+}
+
+func TestColorScaleConstantColumn(t *testing.T) {
+	scale := LinearScale("#000000", "#ffffff")
+	ti := newTestTableImage(t, WithColumns(ColumnSpec{ColorScale: scale}))
+	rows := textRows("5", "5", "5")
+	cols := [][]int{{0}, {0}, {0}}
+	if got, want := ti.columnRanges(rows, cols), map[int][2]float64{0: {5, 5}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ranges = %v, want %v", got, want)
+	}
+	mid := scale.Style(0.5, 0, 1).BgColor
+	want := []string{mid, mid, mid}
+	if got := columnBgColors(t, ti, rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("colors = %v, want %v", got, want)
+	}
+}
+
+func TestColorScaleNonNumericCell(t *testing.T) {
+	scale := LinearScale("#000000", "#ffffff")
+	ti := newTestTableImage(t, WithColumns(ColumnSpec{ColorScale: scale}))
+	rows := textRows("1", "n/a", "NaN", "3")
+	cols := [][]int{{0}, {0}, {0}, {0}}
+	if got, want := ti.columnRanges(rows, cols), map[int][2]float64{0: {1, 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ranges = %v, want %v", got, want)
+	}
+	plain := columnBgColors(t, newTestTableImage(t), rows)
+	want := []string{scale.Style(0, 0, 1).BgColor, plain[1], plain[2], scale.Style(1, 0, 1).BgColor}
+	if got := columnBgColors(t, ti, rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("colors = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,11 @@
+package tableimage
+
+import (
+	// embed document schema
+	_ "embed"
+)
+
+// DocumentSchema JSON Schema of Document for validating JSON and YAML documents
+//
+//go:embed schema.json
+var DocumentSchema string
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"github.com/bububa/tableimage"
+)
+
+// Option handler option interface
+type Option interface {
+	apply(*Handler)
+}
+
+type optionFunc func(*Handler)
+
+func (fn optionFunc) apply(h *Handler) {
+	fn(h)
+}
+
+// WithTableImageOptions set default table image options like font data or DPI, document settings take precedence
+func WithTableImageOptions(options ...tableimage.Option) Option {
+	return optionFunc(func(h *Handler) {
+		h.options = append(h.options, options...)
+	})
+}
+
+// WithFontFolder set font folder, fonts are loaded once and shared by all requests,
+// font folders of posted documents are ignored
+func WithFontFolder(fontFolder string) Option {
+	return optionFunc(func(h *Handler) {
+		h.fontFolder = fontFolder
+		h.fontCache = tableimage.NewFolderFontCache(fontFolder)
+	})
+}
+
+// WithMaxBodySize set max request body size in bytes, defaults to DefaultMaxBodySize
+func WithMaxBodySize(size int64) Option {
+	return optionFunc(func(h *Handler) {
+		h.maxBodySize = size
+	})
+}
+
+// WithMaxRows set max rows of a document, defaults to DefaultMaxRows
+func WithMaxRows(rows int) Option {
+	return optionFunc(func(h *Handler) {
+		h.maxRows = rows
+	})
+}
+
+// WithMaxColumns set max columns of a document counting col spans, defaults to DefaultMaxColumns
+func WithMaxColumns(columns int) Option {
+	return optionFunc(func(h *Handler) {
+		h.maxColumns = columns
+	})
+}
+
+// WithMaxPixels set max width * height of rendered image and of each cell image, defaults to DefaultMaxPixels
+func WithMaxPixels(pixels int) Option {
+	return optionFunc(func(h *Handler) {
+		h.maxPixels = pixels
+	})
+}
+
+// WithRemoteImages allow cell images to be downloaded from remote links, only data URI images are accepted by default,
+// remote images are checked against max pixels like data URI images
+func WithRemoteImages() Option {
+	return optionFunc(func(h *Handler) {
+		h.remoteImages = true
+	})
+}
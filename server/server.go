@@ -0,0 +1,353 @@
+// Package server serves table images rendered from JSON table documents over HTTP
+//
+// Routes:
+//
+//	POST /         render posted document, image type from ?format=png|jpeg|svg|pdf or document image_type, PNG by default
+//	GET  /healthz  health check
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/bububa/tableimage"
+	"github.com/llgcode/draw2d"
+)
+
+const (
+	// DefaultMaxBodySize default max request body size
+	DefaultMaxBodySize int64 = 1 << 20
+	// DefaultMaxRows default max rows of a document
+	DefaultMaxRows = 1000
+	// DefaultMaxColumns default max columns of a document, counting col spans
+	DefaultMaxColumns = 100
+	// DefaultMaxPixels default max width * height of rendered image
+	DefaultMaxPixels = 4096 * 4096
+)
+
+// Handler http handler rendering posted table documents
+type Handler struct {
+	options      []tableimage.Option
+	fontFolder   string
+	fontCache    draw2d.FontCache
+	maxBodySize  int64
+	maxRows      int
+	maxColumns   int
+	maxPixels    int
+	remoteImages bool
+	mux          *http.ServeMux
+}
+
+// New init a Handler
+func New(options ...Option) *Handler {
+	h := &Handler{
+		maxBodySize: DefaultMaxBodySize,
+		maxRows:     DefaultMaxRows,
+		maxColumns:  DefaultMaxColumns,
+		maxPixels:   DefaultMaxPixels,
+		mux:         http.NewServeMux(),
+	}
+	for _, opt := range options {
+		opt.apply(h)
+	}
+	h.mux.HandleFunc("/healthz", h.health)
+	h.mux.HandleFunc("/", h.render)
+	return h
+}
+
+// NewTestServer start a local server with a Handler, a stand-in for the rendering service in tests,
+// the caller should call Close when finished
+func NewTestServer(options ...Option) *httptest.Server {
+	return httptest.NewServer(New(options...))
+}
+
+// ServeHTTP implement http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) health(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	io.WriteString(w, "ok\n")
+}
+
+func (h *Handler) render(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(r.Body, h.maxBodySize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if int64(len(data)) > h.maxBodySize {
+		http.Error(w, fmt.Sprintf("request body exceeds %d bytes", h.maxBodySize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	doc, err := tableimage.ParseDocument(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.prepare(doc, r.URL.Query().Get("format")); err != nil {
+		http.Error(w, err.Error(), statusCode(err))
+		return
+	}
+	etag, err := documentETag(doc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if matchETag(r.Header.Get("If-None-Match"), etag) {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	var buf bytes.Buffer
+	if err := h.write(&buf, doc); err != nil {
+		http.Error(w, err.Error(), statusCode(err))
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", doc.ImageType.ContentType())
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	buf.WriteTo(w)
+}
+
+// limitError request exceeds handler limits
+type limitError struct {
+	msg string
+}
+
+func (e limitError) Error() string {
+	return e.msg
+}
+
+// badRequestError document could not be rendered
+type badRequestError struct {
+	err error
+}
+
+func (e badRequestError) Error() string {
+	return e.err.Error()
+}
+
+func statusCode(err error) int {
+	switch err.(type) {
+	case limitError:
+		return http.StatusRequestEntityTooLarge
+	case badRequestError:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// prepare check document against limits and settle image type, font folder is always taken from handler
+func (h *Handler) prepare(doc *tableimage.Document, format string) error {
+	if len(doc.Rows) == 0 {
+		return badRequestError{errors.New("no rows in document")}
+	}
+	if h.maxRows > 0 && len(doc.Rows) > h.maxRows {
+		return limitError{fmt.Sprintf("document has %d rows, exceeds %d", len(doc.Rows), h.maxRows)}
+	}
+	if err := h.checkSpans(doc); err != nil {
+		return err
+	}
+	if err := h.checkImages(doc); err != nil {
+		return err
+	}
+	if format != "" {
+		if format == "jpg" {
+			format = "jpeg"
+		}
+		if err := doc.ImageType.UnmarshalText([]byte(format)); err != nil {
+			return badRequestError{err}
+		}
+	}
+	if doc.ImageType == 0 {
+		doc.ImageType = tableimage.PNG
+	}
+	doc.FontFolder = ""
+	return nil
+}
+
+// checkSpans check columns of each row and the area covered by cells against max columns before the table is laid out,
+// table layout allocates space for every spanned column
+func (h *Handler) checkSpans(doc *tableimage.Document) error {
+	if h.maxColumns <= 0 {
+		return nil
+	}
+	var area int
+	for rowIdx, row := range doc.Rows {
+		var columns int
+		for _, cell := range row.Cells {
+			span := cell.Span()
+			if span.X > h.maxColumns {
+				return limitError{fmt.Sprintf("cell col span %d exceeds %d columns", span.X, h.maxColumns)}
+			}
+			if rows := len(doc.Rows) - rowIdx; span.Y > rows {
+				span.Y = rows
+			}
+			columns += span.X
+			area += span.X * span.Y
+		}
+		if columns > h.maxColumns {
+			return limitError{fmt.Sprintf("row %d has %d columns, exceeds %d", rowIdx, columns, h.maxColumns)}
+		}
+	}
+	if area > h.maxColumns*len(doc.Rows) {
+		return limitError{fmt.Sprintf("cells cover %d slots, exceeds %d rows * %d columns", area, len(doc.Rows), h.maxColumns)}
+	}
+	return nil
+}
+
+// checkImages block remote images unless allowed and check image sizes before they are decoded,
+// allowed remote images are downloaded here
+func (h *Handler) checkImages(doc *tableimage.Document) error {
+	for _, cell := range documentCells(doc) {
+		if cell.Image == nil || cell.Image.URL == "" || cell.Image.Data != nil {
+			continue
+		}
+		if !strings.HasPrefix(cell.Image.URL, "data:") {
+			if !h.remoteImages {
+				return badRequestError{fmt.Errorf("remote image %q is not allowed, use a data uri", cell.Image.URL)}
+			}
+			if err := h.downloadImage(cell.Image); err != nil {
+				return err
+			}
+			continue
+		}
+		cfg, err := cell.Image.DecodeConfig()
+		if err != nil {
+			return badRequestError{err}
+		}
+		if err := h.checkPixels(cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// downloadImage download remote image, its size is checked against max pixels before its pixels are decoded
+func (h *Handler) downloadImage(img *tableimage.Image) error {
+	resp, err := http.Get(img.URL)
+	if err != nil {
+		return badRequestError{err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return badRequestError{fmt.Errorf("remote image %q: %s", img.URL, resp.Status)}
+	}
+	var head bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(resp.Body, &head))
+	if err != nil {
+		return badRequestError{err}
+	}
+	if err := h.checkPixels(cfg); err != nil {
+		return err
+	}
+	data, _, err := image.Decode(io.MultiReader(&head, resp.Body))
+	if err != nil {
+		return badRequestError{err}
+	}
+	img.Data = data
+	img.UpdateSize()
+	return nil
+}
+
+// checkPixels check image size against max pixels
+func (h *Handler) checkPixels(cfg image.Config) error {
+	if h.maxPixels > 0 && cfg.Width*cfg.Height > h.maxPixels {
+		return limitError{fmt.Sprintf("image size %dx%d exceeds %d pixels", cfg.Width, cfg.Height, h.maxPixels)}
+	}
+	return nil
+}
+
+// write render document with handler options as defaults, image size is checked before drawing,
+// rendering fails without a font instead of returning an image without text
+func (h *Handler) write(w io.Writer, doc *tableimage.Document) error {
+	var options []tableimage.Option
+	if h.fontCache != nil {
+		options = append(options, tableimage.WithFontFolder(h.fontFolder), tableimage.WithFontCache(h.fontCache))
+	}
+	options = append(options, h.options...)
+	ti, err := tableimage.New(append(options, doc.Options()...)...)
+	if err != nil {
+		return err
+	}
+	if !ti.HasFont() {
+		return errors.New("no font loaded, set handler font folder or font file")
+	}
+	table, err := tableimage.NewTable(ti, doc.Rows, doc.Caption, doc.Footer)
+	if err != nil {
+		return badRequestError{err}
+	}
+	size := ti.Size(table)
+	if h.maxPixels > 0 && size.X*size.Y > h.maxPixels {
+		return limitError{fmt.Sprintf("image size %dx%d exceeds %d pixels", size.X, size.Y, h.maxPixels)}
+	}
+	return ti.WriteTable(w, table, doc.ImageType)
+}
+
+// documentCells caption, footer and row cells of document
+func documentCells(doc *tableimage.Document) []*tableimage.Cell {
+	var cells []*tableimage.Cell
+	for _, cell := range []*tableimage.Cell{doc.Caption, doc.Footer} {
+		if cell != nil {
+			cells = append(cells, cell)
+		}
+	}
+	for idx := range doc.Rows {
+		for cellIdx := range doc.Rows[idx].Cells {
+			cells = append(cells, &doc.Rows[idx].Cells[cellIdx])
+		}
+	}
+	return cells
+}
+
+// documentETag strong etag from sha256 of the normalized document
+func documentETag(doc *tableimage.Document) (string, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`, nil
+}
+
+// matchETag check If-None-Match header against etag, weak comparison as in RFC 7232
+func matchETag(header string, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bububa/tableimage"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const testDocument = `{"rows":[{"header":true,"cells":[{"text":"Name"},{"text":"Qty"}]},{"cells":[{"text":"apple"},{"text":"3"}]}]}`
+
+func newTestServer(options ...Option) *httptest.Server {
+	options = append([]Option{WithTableImageOptions(tableimage.WithFontFile(goregular.TTF))}, options...)
+	return NewTestServer(options...)
+}
+
+func post(t *testing.T, url string, body string, header http.Header) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, data
+}
+
+func pngDataURI(t *testing.T, width int, height int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestHealth(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(data) != "ok\n" {
+		t.Errorf("GET /healthz = %d %q, want 200 \"ok\\n\"", resp.StatusCode, data)
+	}
+	resp, _ = post(t, srv.URL+"/healthz", "", nil)
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST /healthz = %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestRenderFormats(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	tests := []struct {
+		query       string
+		document    string
+		status      int
+		contentType string
+		magic       string
+	}{
+		{"", testDocument, http.StatusOK, "image/png", "\x89PNG"},
+		{"?format=png", testDocument, http.StatusOK, "image/png", "\x89PNG"},
+		{"?format=jpeg", testDocument, http.StatusOK, "image/jpeg", "\xff\xd8"},
+		{"?format=jpg", testDocument, http.StatusOK, "image/jpeg", "\xff\xd8"},
+		{"?format=svg", testDocument, http.StatusOK, "image/svg+xml", "<?xml"},
+		{"?format=pdf", testDocument, http.StatusOK, "application/pdf", "%PDF"},
+		{"", `{"image_type":"svg","rows":[{"cells":[{"text":"a"}]}]}`, http.StatusOK, "image/svg+xml", "<?xml"},
+		{"?format=gif", testDocument, http.StatusBadRequest, "", ""},
+		{"", `{"rows":[]}`, http.StatusBadRequest, "", ""},
+		{"", `{"rows":`, http.StatusBadRequest, "", ""},
+	}
+	for _, tt := range tests {
+		resp, data := post(t, srv.URL+"/"+tt.query, tt.document, nil)
+		if resp.StatusCode != tt.status {
+			t.Errorf("POST /%s %s = %d %q, want %d", tt.query, tt.document, resp.StatusCode, data, tt.status)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		if got := resp.Header.Get("Content-Type"); got != tt.contentType {
+			t.Errorf("POST /%s Content-Type = %q, want %q", tt.query, got, tt.contentType)
+		}
+		if !bytes.HasPrefix(data, []byte(tt.magic)) {
+			t.Errorf("POST /%s body doesn't start with %q", tt.query, tt.magic)
+		}
+	}
+}
+
+func TestRenderMethod(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != "POST" {
+		t.Errorf("GET / = %d Allow %q, want 405 Allow POST", resp.StatusCode, resp.Header.Get("Allow"))
+	}
+}
+
+func TestNoFont(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+	}{
+		{"no font options", nil},
+		{"empty font folder", []Option{WithFontFolder(t.TempDir())}},
+	}
+	for _, tt := range tests {
+		srv := NewTestServer(tt.options...)
+		resp, _ := post(t, srv.URL, testDocument, nil)
+		srv.Close()
+		if resp.StatusCode != http.StatusInternalServerError {
+			t.Errorf("%s: status = %d, want %d", tt.name, resp.StatusCode, http.StatusInternalServerError)
+		}
+	}
+}
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []Option
+		document string
+		status   int
+	}{
+		{"body size", []Option{WithMaxBodySize(32)}, testDocument, http.StatusRequestEntityTooLarge},
+		{"rows", []Option{WithMaxRows(1)}, testDocument, http.StatusRequestEntityTooLarge},
+		{"rows within limit", []Option{WithMaxRows(2)}, testDocument, http.StatusOK},
+		{"pixels", []Option{WithMaxPixels(100)}, testDocument, http.StatusRequestEntityTooLarge},
+		{"col span", nil, `{"rows":[{"cells":[{"text":"a","col_span":300000000}]}]}`, http.StatusRequestEntityTooLarge},
+		{"row columns", []Option{WithMaxColumns(3)}, `{"rows":[{"cells":[{"text":"a","col_span":2},{"text":"b","col_span":2}]}]}`, http.StatusRequestEntityTooLarge},
+		{"covered slots", []Option{WithMaxColumns(3)}, `{"rows":[{"cells":[{"text":"a","col_span":3,"row_span":2}]},{"cells":[{"text":"b","col_span":3}]}]}`, http.StatusRequestEntityTooLarge},
+		{"spans within limit", []Option{WithMaxColumns(3)}, `{"rows":[{"cells":[{"text":"a","col_span":3}]},{"cells":[{"text":"b"},{"text":"c"},{"text":"d"}]}]}`, http.StatusOK},
+		{"image pixels", []Option{WithMaxPixels(100 * 100)}, `{"rows":[{"cells":[{"image":{"url":"` + pngDataURI(t, 200, 200) + `"}}]}]}`, http.StatusRequestEntityTooLarge},
+		{"invalid image", nil, `{"rows":[{"cells":[{"image":{"url":"data:image/png;base64,AAAA"}}]}]}`, http.StatusBadRequest},
+		{"font name", nil, `{"style":{"font":{"data":{"name":"../../x"}}},"rows":[{"cells":[{"text":"a"}]}]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(tt.options...)
+			defer srv.Close()
+			resp, data := post(t, srv.URL, tt.document, nil)
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d %q, want %d", resp.StatusCode, data, tt.status)
+			}
+			if tt.status != http.StatusOK && resp.Header.Get("ETag") != "" {
+				t.Errorf("error response has ETag %q", resp.Header.Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	resp, _ := post(t, srv.URL, testDocument, nil)
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		t.Fatalf("POST / = %d ETag %q, want 200 with ETag", resp.StatusCode, etag)
+	}
+	tests := []struct {
+		document    string
+		ifNoneMatch string
+		status      int
+	}{
+		{testDocument, etag, http.StatusNotModified},
+		{testDocument, "W/" + etag, http.StatusNotModified},
+		{testDocument, `"other", ` + etag, http.StatusNotModified},
+		{testDocument, "*", http.StatusNotModified},
+		{testDocument, `"other"`, http.StatusOK},
+		{`{"rows":[{"cells":[{"text":"changed"}]}]}`, etag, http.StatusOK},
+	}
+	for _, tt := range tests {
+		resp, data := post(t, srv.URL, tt.document, http.Header{"If-None-Match": {tt.ifNoneMatch}})
+		if resp.StatusCode != tt.status {
+			t.Errorf("If-None-Match %s = %d, want %d", tt.ifNoneMatch, resp.StatusCode, tt.status)
+		}
+		if tt.status == http.StatusNotModified && (len(data) > 0 || resp.Header.Get("ETag") != etag) {
+			t.Errorf("304 response has body %d bytes, ETag %q", len(data), resp.Header.Get("ETag"))
+		}
+	}
+}
+
+func TestRemoteImages(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(buf.Bytes())
+	}))
+	defer images.Close()
+	document := `{"rows":[{"cells":[{"text":"a","image":{"url":"` + images.URL + `/a.png"}}]}]}`
+	tests := []struct {
+		name    string
+		options []Option
+		status  int
+		message string
+	}{
+		{"blocked by default", nil, http.StatusBadRequest, "not allowed"},
+		{"allowed", []Option{WithRemoteImages()}, http.StatusOK, ""},
+		{"pixels", []Option{WithRemoteImages(), WithMaxPixels(15)}, http.StatusRequestEntityTooLarge, "image size 4x4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(tt.options...)
+			defer srv.Close()
+			resp, data := post(t, srv.URL, document, nil)
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d %q, want %d", resp.StatusCode, data, tt.status)
+			}
+			if tt.message != "" && !bytes.Contains(data, []byte(tt.message)) {
+				t.Errorf("body = %q, want %q", data, tt.message)
+			}
+		})
+	}
+	srv := newTestServer()
+	defer srv.Close()
+	resp, data := post(t, srv.URL, `{"rows":[{"cells":[{"text":"a","image":{"url":"`+pngDataURI(t, 4, 4)+`"}}]}]}`, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("data uri image status = %d %q, want 200", resp.StatusCode, data)
+	}
+}
@@ -0,0 +1,357 @@
+package tableimage
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SliceOption slice loader option interface
+type SliceOption interface {
+	apply(*sliceLoader)
+}
+
+type sliceOptionFunc func(*sliceLoader)
+
+func (fn sliceOptionFunc) apply(l *sliceLoader) {
+	fn(l)
+}
+
+// WithSliceColumns pick and order columns by header name, map keys are sorted by default
+func WithSliceColumns(headers ...string) SliceOption {
+	return sliceOptionFunc(func(l *sliceLoader) {
+		l.columns = headers
+	})
+}
+
+// WithSliceHeader set if a header row is added, defaults to true
+func WithSliceHeader(header bool) SliceOption {
+	return sliceOptionFunc(func(l *sliceLoader) {
+		l.header = header
+	})
+}
+
+// WithSliceNumericAlign set alignment of numeric columns without align tag, defaults to RIGHT
+func WithSliceNumericAlign(align Align) SliceOption {
+	return sliceOptionFunc(func(l *sliceLoader) {
+		l.numericAlign = align
+	})
+}
+
+type sliceLoader struct {
+	columns      []string
+	header       bool
+	numericAlign Align
+}
+
+// sliceColumn column of struct field or map key
+type sliceColumn struct {
+	header  string
+	index   []int
+	key     reflect.Value
+	align   Align
+	format  string
+	order   int
+	ordered bool
+	numeric bool
+}
+
+// SliceRows convert a slice of structs, struct pointers or maps with string keys into rows with a header row.
+// Exported struct fields are columns in declaration order, embedded structs are flattened,
+// field tags like `tableimage:"Price,align=right,order=1,format=%.2f"` set header, alignment, column order and
+// fmt format (or time layout for time.Time), format is the last option and may contain commas,
+// `tableimage:"-"` skips the field.
+// Map keys are columns, numeric columns are right aligned, cells are plain text.
+func SliceRows(v interface{}, options ...SliceOption) ([]Row, error) {
+	l := &sliceLoader{
+		header:       true,
+		numericAlign: RIGHT,
+	}
+	for _, opt := range options {
+		opt.apply(l)
+	}
+	return l.load(reflect.ValueOf(v))
+}
+
+func (l *sliceLoader) load(v reflect.Value) ([]Row, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, errors.New("slice of structs or maps required")
+	}
+	elemType := v.Type().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	var (
+		columns []sliceColumn
+		err     error
+	)
+	switch {
+	case elemType.Kind() == reflect.Struct:
+		columns, err = structColumns(elemType, nil, make(map[reflect.Type]bool))
+	case elemType.Kind() == reflect.Map && elemType.Key().Kind() == reflect.String:
+		columns = mapColumns(v)
+	case elemType.Kind() == reflect.Interface:
+		return nil, errors.New("slice elements must have a concrete struct or map type")
+	default:
+		return nil, fmt.Errorf("unsupported slice element type %s", elemType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns = l.pickColumns(columns)
+	rows := make([]Row, 0, v.Len()+1)
+	if l.header {
+		cells := make([]Cell, 0, len(columns))
+		for _, col := range columns {
+			cell := Cell{
+				Text:       col.header,
+				TextFormat: Plain,
+			}
+			if align := l.columnAlign(col); align != UnknownAlign {
+				cell.Style = &Style{Align: align}
+			}
+			cells = append(cells, cell)
+		}
+		rows = append(rows, Row{
+			Cells:  cells,
+			Header: true,
+		})
+	}
+	for idx := 0; idx < v.Len(); idx++ {
+		elem := indirect(v.Index(idx))
+		cells := make([]Cell, 0, len(columns))
+		for _, col := range columns {
+			var value reflect.Value
+			if elem.IsValid() {
+				if col.index != nil {
+					value = fieldByIndex(elem, col.index)
+				} else {
+					value = elem.MapIndex(col.key)
+				}
+			}
+			cell := Cell{
+				Text:       formatSliceValue(value, col.format),
+				TextFormat: Plain,
+			}
+			if align := l.columnAlign(col); align != UnknownAlign {
+				cell.Style = &Style{Align: align}
+			}
+			cells = append(cells, cell)
+		}
+		rows = append(rows, Row{Cells: cells})
+	}
+	return rows, nil
+}
+
+// pickColumns order columns by order tag, then pick columns set by WithSliceColumns
+func (l *sliceLoader) pickColumns(columns []sliceColumn) []sliceColumn {
+	sort.SliceStable(columns, func(i, j int) bool {
+		if columns[i].ordered != columns[j].ordered {
+			return columns[i].ordered
+		}
+		return columns[i].order < columns[j].order
+	})
+	if l.columns == nil {
+		return columns
+	}
+	picked := make([]sliceColumn, 0, len(l.columns))
+	for _, header := range l.columns {
+		for _, col := range columns {
+			if col.header == header {
+				picked = append(picked, col)
+				break
+			}
+		}
+	}
+	return picked
+}
+
+func (l *sliceLoader) columnAlign(col sliceColumn) Align {
+	if col.align != UnknownAlign {
+		return col.align
+	}
+	if col.numeric {
+		return l.numericAlign
+	}
+	return UnknownAlign
+}
+
+// structColumns columns of exported fields, embedded structs are flattened, like encoding/json each struct type
+// is visited once so that recursive embedding like `type Node struct{ *Node }` terminates
+func structColumns(t reflect.Type, index []int, visited map[reflect.Type]bool) ([]sliceColumn, error) {
+	visited[t] = true
+	var columns []sliceColumn
+	for idx := 0; idx < t.NumField(); idx++ {
+		field := t.Field(idx)
+		tag, hasTag := field.Tag.Lookup("tableimage")
+		if tag == "-" {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), idx)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && !hasTag && fieldType.Kind() == reflect.Struct {
+			if (field.PkgPath != "" && field.Type.Kind() == reflect.Ptr) || visited[fieldType] {
+				continue
+			}
+			embedded, err := structColumns(fieldType, fieldIndex, visited)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, embedded...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		col, err := parseSliceTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if col.header == "" {
+			col.header = field.Name
+		}
+		col.index = fieldIndex
+		col.numeric = isNumericKind(fieldType.Kind())
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// parseSliceTag parse tableimage tag "Header,align=right,order=1,format=%.2f", format is the last option and takes
+// the rest of the tag, so that formats like "Jan 2, 2006" may contain commas
+func parseSliceTag(tag string) (sliceColumn, error) {
+	var col sliceColumn
+	parts := strings.SplitN(tag, ",", 2)
+	col.header = strings.TrimSpace(parts[0])
+	for len(parts) == 2 {
+		rest := strings.TrimLeft(parts[1], " ")
+		if strings.HasPrefix(rest, "format=") {
+			col.format = strings.TrimPrefix(rest, "format=")
+			break
+		}
+		parts = strings.SplitN(rest, ",", 2)
+		part := strings.TrimSpace(parts[0])
+		if part == "" {
+			continue
+		}
+		idx := strings.IndexByte(part, '=')
+		if idx < 0 {
+			return col, fmt.Errorf("invalid tableimage tag option %q", part)
+		}
+		key, value := part[:idx], part[idx+1:]
+		switch key {
+		case "align":
+			if err := col.align.UnmarshalText([]byte(value)); err != nil {
+				return col, err
+			}
+		case "order":
+			order, err := strconv.Atoi(value)
+			if err != nil {
+				return col, fmt.Errorf("invalid tableimage tag order %q", value)
+			}
+			col.order = order
+			col.ordered = true
+		default:
+			return col, fmt.Errorf("unknown tableimage tag option %q", key)
+		}
+	}
+	return col, nil
+}
+
+// mapColumns sorted keys of all maps, columns with numeric values only are numeric
+func mapColumns(v reflect.Value) []sliceColumn {
+	keys := make(map[string]reflect.Value)
+	numeric := make(map[string]bool)
+	for idx := 0; idx < v.Len(); idx++ {
+		elem := indirect(v.Index(idx))
+		if !elem.IsValid() {
+			continue
+		}
+		iter := elem.MapRange()
+		for iter.Next() {
+			name := iter.Key().String()
+			if _, found := keys[name]; !found {
+				keys[name] = iter.Key()
+				numeric[name] = true
+			}
+			if value := indirect(iter.Value()); value.IsValid() && !isNumericKind(value.Kind()) {
+				numeric[name] = false
+			}
+		}
+	}
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	columns := make([]sliceColumn, 0, len(names))
+	for _, name := range names {
+		columns = append(columns, sliceColumn{
+			header:  name,
+			key:     keys[name],
+			numeric: numeric[name],
+		})
+	}
+	return columns
+}
+
+// fieldByIndex nested field by index, invalid if an embedded pointer is nil
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for idx, i := range index {
+		if idx > 0 {
+			v = indirect(v)
+			if !v.IsValid() {
+				return v
+			}
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+// indirect dereference pointers and interfaces, invalid for nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// formatSliceValue format value with fmt format, or time layout for time.Time, nil values are empty
+func formatSliceValue(v reflect.Value, format string) string {
+	v = indirect(v)
+	if !v.IsValid() || !v.CanInterface() {
+		return ""
+	}
+	value := v.Interface()
+	if format == "" {
+		return fmt.Sprint(value)
+	}
+	if t, ok := value.(time.Time); ok && !strings.Contains(format, "%") {
+		return t.Format(format)
+	}
+	return fmt.Sprintf(format, value)
+}
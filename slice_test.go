@@ -0,0 +1,74 @@
+package tableimage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSliceTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want sliceColumn
+	}{
+		{"Name", sliceColumn{header: "Name"}},
+		{"Price,align=right,order=1,format=%.2f", sliceColumn{header: "Price", align: RIGHT, order: 1, ordered: true, format: "%.2f"}},
+		{"Date, order=2, format=Jan 2, 2006", sliceColumn{header: "Date", order: 2, ordered: true, format: "Jan 2, 2006"}},
+		{",format=%d, %d,", sliceColumn{format: "%d, %d,"}},
+		{"Note,,align=center,", sliceColumn{header: "Note", align: CENTER}},
+	}
+	for _, tt := range tests {
+		got, err := parseSliceTag(tt.tag)
+		if err != nil {
+			t.Errorf("parseSliceTag(%q) error %v", tt.tag, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSliceTag(%q) = %+v, want %+v", tt.tag, got, tt.want)
+		}
+	}
+	for _, tag := range []string{"Name,align", "Name,order=x", "Name,size=1", "Name,align=sideways"} {
+		if _, err := parseSliceTag(tag); err == nil {
+			t.Errorf("parseSliceTag(%q) got no error", tag)
+		}
+	}
+}
+
+func TestSliceRowsFormatWithComma(t *testing.T) {
+	type entry struct {
+		Day   time.Time `tableimage:"Day,format=Jan 2, 2006"`
+		Total float64   `tableimage:"Total,order=-1,format=%.1f"`
+	}
+	rows, err := SliceRows([]entry{{Day: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), Total: 1.25}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"Total", "Day"}, {"1.2", "Mar 5, 2024"}}
+	if got := tableTexts(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
+
+// TreeNode embeds itself and TreeLeaf which embeds TreeNode back
+type TreeNode struct {
+	Name string
+	*TreeNode
+	*TreeLeaf
+}
+
+// TreeLeaf embedded by TreeNode
+type TreeLeaf struct {
+	Value int
+	*TreeNode
+}
+
+func TestSliceRowsRecursiveEmbedding(t *testing.T) {
+	rows, err := SliceRows([]TreeNode{{Name: "a", TreeLeaf: &TreeLeaf{Value: 1}}, {Name: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"Name", "Value"}, {"a", "1"}, {"b", ""}}
+	if got := tableTexts(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
@@ -53,6 +53,13 @@ var DefaultFooterStyle = func() *Style {
 	}
 }
 
+// DefaultHeaderStyle default header row style, bold font and bottom rule are derived from table style
+var DefaultHeaderStyle = func() *Style {
+	return &Style{
+		BgColor: DefaultHeaderBgColor,
+	}
+}
+
 // DefaultLine default line setting
 var DefaultLine = func() Line {
 	return Line{
@@ -312,11 +319,11 @@ func (b Border) Size() image.Point {
 }
 
 // Draw a border
-func (b Border) Draw(img *image.RGBA, bounds image.Rectangle) {
-	b.Top.Draw(img, image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Max.X, bounds.Min.Y))
-	b.Right.Draw(img, image.Rect(bounds.Max.X, bounds.Min.Y, bounds.Max.X, bounds.Max.Y))
-	b.Bottom.Draw(img, image.Rect(bounds.Min.X, bounds.Max.Y, bounds.Max.X, bounds.Max.Y))
-	b.Left.Draw(img, image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Min.X, bounds.Max.Y))
+func (b Border) Draw(r Renderer, bounds image.Rectangle) {
+	b.Top.Draw(r, image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Max.X, bounds.Min.Y))
+	b.Right.Draw(r, image.Rect(bounds.Max.X, bounds.Min.Y, bounds.Max.X, bounds.Max.Y))
+	b.Bottom.Draw(r, image.Rect(bounds.Min.X, bounds.Max.Y, bounds.Max.X, bounds.Max.Y))
+	b.Left.Draw(r, image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Min.X, bounds.Max.Y))
 }
 
 // Line border line
@@ -338,11 +345,11 @@ func (l Line) ChangeWidth(width int) Line {
 }
 
 // Draw a new line
-func (l Line) Draw(img *image.RGBA, bounds image.Rectangle) {
+func (l Line) Draw(r Renderer, bounds image.Rectangle) {
 	if l.Width == 0 {
 		return
 	}
-	drawRect(img, bounds, l.Color, l.Color, float64(l.Width))
+	r.StrokeLine(bounds.Min, bounds.Max, l.Color, l.Width)
 }
 
 // ZeroPadding zero padding object
@@ -420,10 +427,67 @@ type Font struct {
 	Font *truetype.Font `json:"-"`
 	// DPI
 	DPI int `json:"dpi,omitempty"`
+	// cache font cache the font loaded from, used to load font variants of text runs
+	cache draw2d.FontCache
+}
+
+// Variant returns a copy of font with style bits added loaded from font cache, returns f itself if the variant can't be loaded
+func (f *Font) Variant(style draw2d.FontStyle, cache draw2d.FontCache) *Font {
+	if f.Data == nil || f.Data.Style|style == f.Data.Style || cache == nil {
+		return f
+	}
+	data := *f.Data
+	data.Style |= style
+	ft, err := cache.Load(data)
+	if err != nil {
+		return f
+	}
+	return &Font{
+		Size:  f.Size,
+		Data:  &data,
+		Font:  ft,
+		DPI:   f.DPI,
+		cache: cache,
+	}
+}
+
+// derive font for a text run with its font name, size, monospace family and style bits, returns f itself if nothing changes,
+// keeps the font face if the variant can't be loaded
+func (f *Font) derive(run Text) *Font {
+	if f == nil {
+		return nil
+	}
+	ret := *f
+	if run.FontSize > 0 {
+		ret.Size = run.FontSize
+	}
+	if f.Data != nil && f.cache != nil {
+		data := *f.Data
+		if run.FontName != "" && CheckFontName(run.FontName) == nil {
+			data.Name = run.FontName
+		}
+		if run.Mono {
+			data.Family = draw2d.FontFamilyMono
+		}
+		data.Style |= run.fontStyle()
+		if data != *f.Data {
+			if ft, err := f.cache.Load(data); err == nil {
+				ret.Data = &data
+				ret.Font = ft
+			}
+		}
+	}
+	if ret.Size == f.Size && ret.Font == f.Font {
+		return f
+	}
+	return &ret
 }
 
 // Load font from font cache
 func (f *Font) Load(cache draw2d.FontCache) error {
+	if cache != nil {
+		f.cache = cache
+	}
 	if f.Font != nil {
 		return nil
 	}
@@ -0,0 +1,204 @@
+package tableimage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"strconv"
+
+	"github.com/llgcode/draw2d"
+)
+
+// svgRenderer renders table as vector svg document, rows and cells are groups with aria table roles
+type svgRenderer struct {
+	buf   bytes.Buffer
+	size  image.Point
+	clips int
+	// table rows were tagged
+	table bool
+	// header current row is a header row
+	header bool
+}
+
+func newSVGRenderer(size image.Point) *svgRenderer {
+	return &svgRenderer{size: size}
+}
+
+// FillRect implement Renderer
+func (r *svgRenderer) FillRect(bounds image.Rectangle, color string) {
+	if svgColor(color) == "" {
+		return
+	}
+	fmt.Fprintf(&r.buf, `<rect x="%d" y="%d" width="%d" height="%d" %s/>`+"\n", bounds.Min.X, bounds.Min.Y, bounds.Dx(), bounds.Dy(), svgPaint("fill", color))
+}
+
+// StrokeLine implement Renderer
+func (r *svgRenderer) StrokeLine(from image.Point, to image.Point, color string, width int) {
+	if width == 0 || svgColor(color) == "" {
+		return
+	}
+	fmt.Fprintf(&r.buf, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke-width="%d" %s/>`+"\n", from.X, from.Y, to.X, to.Y, width, svgPaint("stroke", color))
+}
+
+// DrawGlyphRun implement Renderer
+func (r *svgRenderer) DrawGlyphRun(pt image.Point, run GlyphRun) {
+	font := run.Font
+	if font == nil || font.Font == nil || run.Text == "" || svgColor(run.Color) == "" {
+		return
+	}
+	fmt.Fprintf(&r.buf, `<text x="%d" y="%d" font-size="%s"%s xml:space="preserve" %s`, pt.X, pt.Y+int(font.Size), formatFloat(font.Size), svgFontAttrs(font), svgPaint("fill", run.Color))
+	if run.Width > 0 {
+		fmt.Fprintf(&r.buf, ` textLength="%d" lengthAdjust="spacingAndGlyphs"`, run.Width)
+	}
+	r.buf.WriteString(">")
+	xml.EscapeText(&r.buf, []byte(run.Text))
+	r.buf.WriteString("</text>\n")
+}
+
+// DrawImage implement Renderer
+func (r *svgRenderer) DrawImage(img *Image, pt image.Point) {
+	if img == nil || img.Data == nil {
+		return
+	}
+	uri, err := imageDataURI(img.Data)
+	if err != nil {
+		return
+	}
+	bounds := img.Data.Bounds()
+	scale := img.Scale()
+	width := float64(bounds.Dx()) * scale
+	height := float64(bounds.Dy()) * scale
+	fmt.Fprintf(&r.buf, `<image x="%d" y="%d" width="%s" height="%s" preserveAspectRatio="xMinYMin meet" xlink:href="%s"/>`+"\n", pt.X, pt.Y, formatFloat(width), formatFloat(height), uri)
+}
+
+// PushClip implement Renderer
+func (r *svgRenderer) PushClip(bounds image.Rectangle) {
+	r.clips++
+	fmt.Fprintf(&r.buf, `<clipPath id="clip%d"><rect x="%d" y="%d" width="%d" height="%d"/></clipPath>`+"\n", r.clips, bounds.Min.X, bounds.Min.Y, bounds.Dx(), bounds.Dy())
+	fmt.Fprintf(&r.buf, `<g clip-path="url(#clip%d)">`+"\n", r.clips)
+}
+
+// PopClip implement Renderer
+func (r *svgRenderer) PopClip() {
+	r.buf.WriteString("</g>\n")
+}
+
+// BeginRow implement CellRenderer
+func (r *svgRenderer) BeginRow(header bool) {
+	r.table = true
+	r.header = header
+	r.buf.WriteString(`<g role="row">` + "\n")
+}
+
+// EndRow implement CellRenderer
+func (r *svgRenderer) EndRow() {
+	r.buf.WriteString("</g>\n")
+}
+
+// BeginCell implement CellRenderer, cells of header rows are column headers
+func (r *svgRenderer) BeginCell() {
+	role := "cell"
+	if r.header {
+		role = "columnheader"
+	}
+	fmt.Fprintf(&r.buf, `<g role="%s">`+"\n", role)
+}
+
+// EndCell implement CellRenderer
+func (r *svgRenderer) EndCell() {
+	r.buf.WriteString("</g>\n")
+}
+
+// WriteTo write svg document to io.Writer
+func (r *svgRenderer) WriteTo(w io.Writer) (int64, error) {
+	var doc bytes.Buffer
+	fmt.Fprintf(&doc, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	var role string
+	if r.table {
+		role = ` role="table"`
+	}
+	fmt.Fprintf(&doc, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%d" height="%d" viewBox="0 0 %d %d"%s>`+"\n", r.size.X, r.size.Y, r.size.X, r.size.Y, role)
+	doc.Write(r.buf.Bytes())
+	doc.WriteString("</svg>\n")
+	return doc.WriteTo(w)
+}
+
+// writeSVGImage wraps a raster image into a svg document
+func writeSVGImage(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	r := newSVGRenderer(bounds.Size())
+	r.DrawImage(&Image{Data: img, Size: bounds.Size()}, image.ZP)
+	_, err := r.WriteTo(w)
+	return err
+}
+
+// imageDataURI encode image as png data uri
+func imageDataURI(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// svgColor convert hex color to svg rgb color, returns empty string for transparent color
+func svgColor(hexColor string) string {
+	c := ColorFromHex(hexColor)
+	if c.A == 0 {
+		return ""
+	}
+	return fmt.Sprintf("rgb(%d,%d,%d)", c.R, c.G, c.B)
+}
+
+// svgPaint fill/stroke attributes with opacity
+func svgPaint(attr string, hexColor string) string {
+	c := ColorFromHex(hexColor)
+	paint := fmt.Sprintf(`%s="%s"`, attr, svgColor(hexColor))
+	if c.A < 255 {
+		paint += fmt.Sprintf(` %s-opacity="%s"`, attr, formatFloat(float64(c.A)/255))
+	}
+	return paint
+}
+
+// svgFontAttrs font-family/font-weight/font-style attributes from font data
+func svgFontAttrs(font *Font) string {
+	if font.Data == nil {
+		return ""
+	}
+	var (
+		attrs   string
+		generic = "sans-serif"
+	)
+	switch font.Data.Family {
+	case draw2d.FontFamilySerif:
+		generic = "serif"
+	case draw2d.FontFamilyMono:
+		generic = "monospace"
+	}
+	if font.Data.Name != "" {
+		attrs += fmt.Sprintf(` font-family="%s, %s"`, escapeAttr(font.Data.Name), generic)
+	} else {
+		attrs += fmt.Sprintf(` font-family="%s"`, generic)
+	}
+	if font.Data.Style&draw2d.FontStyleBold != 0 {
+		attrs += ` font-weight="bold"`
+	}
+	if font.Data.Style&draw2d.FontStyleItalic != 0 {
+		attrs += ` font-style="italic"`
+	}
+	return attrs
+}
+
+func escapeAttr(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,92 @@
+package tableimage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// svgTextRoles innermost aria role of each svg text element by text
+func svgTextRoles(t *testing.T, data []byte) (string, map[string]string) {
+	t.Helper()
+	var (
+		svgRole string
+		roles   = make(map[string]string)
+		stack   []string
+		inText  bool
+	)
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			var role string
+			for _, attr := range el.Attr {
+				if attr.Name.Local == "role" {
+					role = attr.Value
+				}
+			}
+			switch el.Name.Local {
+			case "svg":
+				svgRole = role
+			case "g":
+				if role == "" && len(stack) > 0 {
+					role = stack[len(stack)-1]
+				}
+				stack = append(stack, role)
+			case "text":
+				inText = true
+			}
+		case xml.EndElement:
+			switch el.Name.Local {
+			case "g":
+				stack = stack[:len(stack)-1]
+			case "text":
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				var role string
+				if len(stack) > 0 {
+					role = stack[len(stack)-1]
+				}
+				roles[string(el)] = role
+			}
+		}
+	}
+	return svgRole, roles
+}
+
+func TestSVGHeaderRoles(t *testing.T) {
+	ti := newTestTableImage(t)
+	rows := []Row{
+		{Header: true, Cells: []Cell{{Text: "Name"}, {Text: "Qty"}}},
+		{Cells: []Cell{{Text: "apple"}, {Text: "3"}}},
+	}
+	var buf bytes.Buffer
+	if err := ti.Write(&buf, rows, &Cell{Text: "Fruits"}, nil, SVG); err != nil {
+		t.Fatal(err)
+	}
+	svgRole, roles := svgTextRoles(t, buf.Bytes())
+	if svgRole != "table" {
+		t.Errorf("svg role = %q, want table", svgRole)
+	}
+	want := map[string]string{
+		"Fruits": "",
+		"Name":   "columnheader",
+		"Qty":    "columnheader",
+		"apple":  "cell",
+		"3":      "cell",
+	}
+	if !reflect.DeepEqual(roles, want) {
+		t.Errorf("text roles = %v, want %v", roles, want)
+	}
+}
@@ -11,6 +11,7 @@ type Table struct {
 	colsWidth   []int
 	rowsHeight  []int
 	rows        []Row
+	cols        [][]int
 	caption     *Cell
 	footer      *Cell
 	captionSize image.Point
@@ -19,60 +20,205 @@ type Table struct {
 
 // NewTable create Table instance
 func NewTable(ti *TableImage, rows []Row, caption *Cell, footer *Cell) (*Table, error) {
-	rows, cols, heights := initRows(ti, rows)
+	for _, cell := range []*Cell{caption, footer} {
+		if cell != nil {
+			*cell = ti.applyTextFormat(cell.renderValue())
+			cell.hyphenator = ti.hyphenator
+		}
+	}
+	if ti.strictMarkup {
+		if err := ti.validateMarkup(rows, caption, footer); err != nil {
+			return nil, err
+		}
+	}
+	rows, cols, widths, heights := initRows(ti, rows)
+	if resolved := ti.resolveColumns(rows, cols, widths); resolved != nil {
+		rows = rewrapRows(rows, cols, widths, resolved)
+		alignDecimals(rows, cols)
+		_, heights = measureRows(rows, cols, len(widths))
+	}
 	table := &Table{
 		caption:    caption,
 		footer:     footer,
 		rows:       rows,
+		cols:       cols,
 		rowsHeight: heights,
-		colsWidth:  cols,
+		colsWidth:  widths,
 	}
-	table.initCaption(ti.fontCache)
-	table.initFooter(ti.fontCache)
+	table.initCaption(ti.style, ti.fontCache)
+	table.initFooter(ti.style, ti.fontCache)
 	return table, nil
 }
 
-func initRows(ti *TableImage, rows []Row) ([]Row, []int, []int) {
-	var maxCols int
+// validateMarkup check inline markup of all cells, caption and footer
+func (ti *TableImage) validateMarkup(rows []Row, caption *Cell, footer *Cell) error {
 	for _, row := range rows {
-		cols := len(row.Cells)
-		if cols > maxCols {
-			maxCols = cols
+		for _, cell := range row.Cells {
+			if err := ti.applyTextFormat(cell.renderValue()).Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	for _, cell := range []*Cell{caption, footer} {
+		if cell == nil {
+			continue
+		}
+		if err := cell.Validate(); err != nil {
+			return err
 		}
 	}
-	cols := make([]int, maxCols)
-	heights := make([]int, len(rows))
+	return nil
+}
+
+func initRows(ti *TableImage, rows []Row) ([]Row, [][]int, []int, []int) {
+	rows, cols, maxCols := placeCells(rows)
 	updatedRows := make([]Row, 0, len(rows))
+	ranges := ti.columnRanges(rows, cols)
+	barRanges := dataBarRanges(rows, cols)
+	var dataRowIdx int
 	for rowIdx, row := range rows {
+		parentStyle := ti.style
+		if row.Header && ti.headerStyle != nil {
+			parentStyle = ti.headerStyle
+		}
+		var rowOwnStyle *Style
 		if row.Style == nil {
-			row.Style = ti.style
+			row.Style = parentStyle
 		} else {
-			row.Style.Inherit(ti.style, ti.fontCache)
+			ownStyle := *row.Style
+			rowOwnStyle = &ownStyle
+			row.Style.Inherit(parentStyle, ti.fontCache)
 		}
 		rowCells := make([]Cell, 0, len(row.Cells))
 		for cellIdx, cell := range row.Cells {
+			colIdx := cols[rowIdx][cellIdx]
+			cell = cell.renderValue()
+			if !row.Header {
+				cell = ti.applyColorScale(colIdx, cell, ranges)
+				cell = ti.applyRules(dataRowIdx, colIdx, cell)
+			}
+			cell = cell.applyDataBarRange(colIdx, barRanges)
+			cell = ti.applyTextFormat(cell)
+			cell.hyphenator = ti.hyphenator
+			cellParentStyle := ti.columnParentStyle(colIdx, row.Style, rowOwnStyle, parentStyle)
 			if cell.Style == nil {
-				cell.Style = row.Style
+				cell.Style = cellParentStyle
 			} else {
-				cell.Style.Inherit(row.Style, ti.fontCache)
+				cell.Style.Inherit(cellParentStyle, ti.fontCache)
 			}
 			cell.GetImage(ti.imageCache)
+			rowCells = append(rowCells, cell)
+		}
+		row.Cells = rowCells
+		updatedRows = append(updatedRows, row)
+		if !row.Header {
+			dataRowIdx++
+		}
+	}
+	alignDecimals(updatedRows, cols)
+	widths, heights := measureRows(updatedRows, cols, maxCols)
+	return updatedRows, cols, widths, heights
+}
+
+// measureRows measure columns width and rows height, spanning cells distribute their size across the slots they cover
+func measureRows(rows []Row, cols [][]int, maxCols int) ([]int, []int) {
+	var (
+		widths  = make([]int, maxCols)
+		heights = make([]int, len(rows))
+		spanned []image.Point
+	)
+	for rowIdx, row := range rows {
+		for cellIdx, cell := range row.Cells {
+			span := cell.Span()
+			if span.X > 1 || span.Y > 1 {
+				spanned = append(spanned, image.Pt(cellIdx, rowIdx))
+			}
 			cellSize := cell.Size()
-			if cellSize.X > cols[cellIdx] {
-				cols[cellIdx] = cellSize.X
+			colIdx := cols[rowIdx][cellIdx]
+			if span.X == 1 && cellSize.X > widths[colIdx] {
+				widths[colIdx] = cellSize.X
 			}
-			if cellSize.Y > heights[rowIdx] {
+			if span.Y == 1 && cellSize.Y > heights[rowIdx] {
 				heights[rowIdx] = cellSize.Y
 			}
-			rowCells = append(rowCells, cell)
 		}
-		row.Cells = rowCells
-		updatedRows = append(updatedRows, row)
 	}
-	return updatedRows, cols, heights
+	for _, pt := range spanned {
+		cell := rows[pt.Y].Cells[pt.X]
+		span := cell.Span()
+		cellSize := cell.Size()
+		colIdx := cols[pt.Y][pt.X]
+		distributeSpan(widths[colIdx:colIdx+span.X], cellSize.X)
+		distributeSpan(heights[pt.Y:pt.Y+span.Y], cellSize.Y)
+	}
+	return widths, heights
 }
 
-func (r *Table) initCaption(cache draw2d.FontCache) {
+// placeCells assign each cell a start column, skipping slots covered by spanning cells above
+// returns rows with spans clamped to table bounds, cells start columns and columns count.
+// Col spans are clamped to the cells count of the table, more columns couldn't hold any cell.
+func placeCells(rows []Row) ([]Row, [][]int, int) {
+	var (
+		maxCols    int
+		cellsCount int
+		occupied   = make([]map[int]bool, len(rows))
+		cols       = make([][]int, len(rows))
+		ret        = make([]Row, 0, len(rows))
+	)
+	for rowIdx, row := range rows {
+		occupied[rowIdx] = make(map[int]bool)
+		cellsCount += len(row.Cells)
+	}
+	for rowIdx, row := range rows {
+		var colIdx int
+		cells := make([]Cell, 0, len(row.Cells))
+		cols[rowIdx] = make([]int, 0, len(row.Cells))
+		for _, cell := range row.Cells {
+			for occupied[rowIdx][colIdx] {
+				colIdx++
+			}
+			span := cell.Span()
+			if rowIdx+span.Y > len(rows) {
+				span.Y = len(rows) - rowIdx
+			}
+			if span.X > cellsCount {
+				span.X = cellsCount
+			}
+			cell.ColSpan = span.X
+			cell.RowSpan = span.Y
+			for y := rowIdx; y < rowIdx+span.Y; y++ {
+				for x := colIdx; x < colIdx+span.X; x++ {
+					occupied[y][x] = true
+				}
+			}
+			cols[rowIdx] = append(cols[rowIdx], colIdx)
+			cells = append(cells, cell)
+			colIdx += span.X
+			if colIdx > maxCols {
+				maxCols = colIdx
+			}
+		}
+		row.Cells = cells
+		ret = append(ret, row)
+	}
+	return ret, cols, maxCols
+}
+
+// distributeSpan grow sizes evenly so that they sum up to at least total
+func distributeSpan(sizes []int, total int) {
+	var current int
+	for _, v := range sizes {
+		current += v
+	}
+	extra := total - current
+	if extra <= 0 || len(sizes) == 0 {
+		return
+	}
+	distributeEvenly(sizes, extra)
+}
+
+// initCaption init caption style, font face and data missing in caption style fall back to table font
+func (r *Table) initCaption(tableStyle *Style, cache draw2d.FontCache) {
 	if r.caption == nil {
 		return
 	}
@@ -82,13 +228,17 @@ func (r *Table) initCaption(cache draw2d.FontCache) {
 	} else {
 		r.caption.Style.Inherit(DefaultCaptionStyle(), cache)
 	}
+	if tableStyle != nil {
+		r.caption.Style.inheritFont(tableStyle, cache)
+	}
 	if r.caption.Style.MaxWidth == 0 || r.caption.Style.MaxWidth > r.Size().X {
 		r.caption.Style.MaxWidth = r.Size().X - r.caption.Style.BorderPadding().Size().X
 	}
 	r.captionSize = r.caption.Size()
 }
 
-func (r *Table) initFooter(cache draw2d.FontCache) {
+// initFooter init footer style, font face and data missing in footer style fall back to table font
+func (r *Table) initFooter(tableStyle *Style, cache draw2d.FontCache) {
 	if r.footer == nil {
 		return
 	}
@@ -98,6 +248,9 @@ func (r *Table) initFooter(cache draw2d.FontCache) {
 	} else {
 		r.footer.Style.Inherit(DefaultFooterStyle(), cache)
 	}
+	if tableStyle != nil {
+		r.footer.Style.inheritFont(tableStyle, cache)
+	}
 	if r.footer.Style.MaxWidth == 0 || r.footer.Style.MaxWidth > r.Size().X {
 		r.footer.Style.MaxWidth = r.Size().X - r.footer.Style.BorderPadding().Size().X
 	}
@@ -138,46 +291,60 @@ func (r Table) RowsStartPoint() image.Point {
 }
 
 // DrawCaption draw table caption
-func (r Table) DrawCaption(img *image.RGBA, pt image.Point) {
+func (r Table) DrawCaption(rd Renderer, pt image.Point) {
 	if r.caption == nil {
 		return
 	}
 	bounds := image.Rect(pt.X, pt.Y, pt.X+r.Size().X, pt.Y+r.captionSize.Y)
-	r.caption.Draw(img, bounds)
+	r.caption.Draw(rd, bounds)
 }
 
 // DrawFooter draw table footer
-func (r Table) DrawFooter(img *image.RGBA, pt image.Point) {
+func (r Table) DrawFooter(rd Renderer, pt image.Point) {
 	if r.footer == nil {
 		return
 	}
 	bounds := image.Rect(pt.X, pt.Y, pt.X+r.Size().X, pt.Y+r.footerSize.Y)
-	r.footer.Draw(img, bounds)
+	r.footer.Draw(rd, bounds)
 }
 
-// CellBounds get a cell bounds
+// CellBounds get a cell bounds, spanning cells get the merged bounds of the slots they cover
 func (r Table) CellBounds(rowIdx int, cellIdx int) image.Rectangle {
 	var (
-		x int
-		y int
-		w = r.colsWidth[cellIdx]
-		h = r.rowsHeight[rowIdx]
+		x      int
+		y      int
+		w      int
+		h      int
+		colIdx = r.cols[rowIdx][cellIdx]
+		span   = r.rows[rowIdx].Cells[cellIdx].Span()
 	)
 	for i, v := range r.rowsHeight {
-		if i > rowIdx-1 {
-			break
+		if i < rowIdx {
+			y += v
+		} else if i < rowIdx+span.Y {
+			h += v
 		}
-		y += v
 	}
 	for i, v := range r.colsWidth {
-		if i > cellIdx-1 {
-			break
+		if i < colIdx {
+			x += v
+		} else if i < colIdx+span.X {
+			w += v
 		}
-		x += v
 	}
 	return image.Rect(x, y, x+w, y+h)
 }
 
+// HeaderRows count of leading rows marked as header
+func (r Table) HeaderRows() int {
+	for idx, row := range r.rows {
+		if !row.Header {
+			return idx
+		}
+	}
+	return len(r.rows)
+}
+
 // Rows get rows
 func (r Table) Rows() []Row {
 	return r.rows
@@ -3,21 +3,33 @@ package tableimage
 import (
 	"errors"
 	"image"
-	"image/draw"
 	"image/jpeg"
 	"image/png"
 	"io"
 	"os"
 
+	"github.com/golang/freetype/truetype"
 	"github.com/llgcode/draw2d"
 )
 
 // TableImage core struct
 type TableImage struct {
-	fontFolder string
-	fontCache  draw2d.FontCache
-	imageCache ImageCache
-	style      *Style
+	fontFolder       string
+	fontCache        draw2d.FontCache
+	fontFile         []byte
+	fontFiles        map[*truetype.Font][]byte
+	imageCache       ImageCache
+	style            *Style
+	headerStyle      *Style
+	headerRows       int
+	pageNumber       string
+	columns          []ColumnSpec
+	columnPrecedence StylePrecedence
+	tableWidth       int
+	rules            []Rule
+	strictMarkup     bool
+	textFormat       TextFormat
+	hyphenator       Hyphenator
 }
 
 // New init a TableImage object
@@ -29,39 +41,126 @@ func New(options ...Option) (*TableImage, error) {
 	for _, opt := range options {
 		opt.apply(ti)
 	}
-	if ti.fontFolder != "" {
-		ti.fontCache = draw2d.NewSyncFolderFontCache(ti.fontFolder)
-		if ti.style != nil {
-			if err := ti.style.LoadFont(ti.fontCache); err != nil {
-				return nil, err
-			}
+	if ti.fontFolder != "" && ti.fontCache == nil {
+		ti.fontCache = NewFolderFontCache(ti.fontFolder)
+	}
+	if ti.fontFile != nil {
+		if err := ti.loadFontFile(); err != nil {
+			return nil, err
+		}
+	}
+	if ti.fontCache != nil && ti.style != nil {
+		if err := ti.style.LoadFont(ti.fontCache); err != nil {
+			return nil, err
 		}
 	}
+	if ti.headerStyle == nil {
+		ti.headerStyle = ti.defaultHeaderStyle()
+	} else if err := ti.headerStyle.Inherit(ti.style, ti.fontCache); err != nil {
+		return nil, err
+	}
 	return ti, nil
 }
 
+// loadFontFile parse font file set by WithFontFile as table font, the file is kept for pdf output
+func (ti *TableImage) loadFontFile() error {
+	ft, err := truetype.Parse(ti.fontFile)
+	if err != nil {
+		return err
+	}
+	if ti.style == nil {
+		ti.style = &Style{}
+	}
+	if ti.style.Font == nil {
+		ti.style.Font = &Font{}
+	}
+	ti.style.Font.Font = ft
+	ti.fontFiles = map[*truetype.Font][]byte{ft: ti.fontFile}
+	return nil
+}
+
+// applyTextFormat set table text format to cell without its own text format
+func (ti *TableImage) applyTextFormat(cell Cell) Cell {
+	if cell.TextFormat == UnknownTextFormat {
+		cell.TextFormat = ti.textFormat
+	}
+	return cell
+}
+
+// defaultHeaderStyle header style derived from table style, bold font with a double width bottom border
+func (ti *TableImage) defaultHeaderStyle() *Style {
+	style := DefaultHeaderStyle()
+	if ti.style == nil {
+		return style
+	}
+	if ti.style.Border != nil {
+		border := *ti.style.Border
+		border.Bottom = border.Bottom.ChangeWidth(border.Bottom.Width * 2)
+		style.Border = &border
+	}
+	if ti.style.Font != nil {
+		style.Font = ti.style.Font.Variant(draw2d.FontStyleBold, ti.fontCache)
+	}
+	style.Inherit(ti.style, ti.fontCache)
+	return style
+}
+
 // Draw draw table image
 func (ti *TableImage) Draw(rows []Row, caption *Cell, footer *Cell) (*image.RGBA, error) {
 	table, err := NewTable(ti, rows, caption, footer)
 	if err != nil {
 		return nil, err
 	}
-	bounds := ti.Size(table)
-	img := image.NewRGBA(image.Rect(0, 0, bounds.X, bounds.Y))
-	if ti.style != nil && ti.style.BgColor != "" {
-		draw.Draw(img, img.Bounds(), &image.Uniform{ColorFromHex(ti.style.BgColor)}, image.ZP, draw.Src)
+	return ti.drawTable(table), nil
+}
+
+// Write render table and write it to io Writer, vector image types keep text and borders as vectors
+func (ti *TableImage) Write(w io.Writer, rows []Row, caption *Cell, footer *Cell, imageType ImageType) error {
+	table, err := NewTable(ti, rows, caption, footer)
+	if err != nil {
+		return err
+	}
+	return ti.WriteTable(w, table, imageType)
+}
+
+// WriteTable render a prepared table and write it to io Writer, table size could be checked with ti.Size before rendering
+func (ti *TableImage) WriteTable(w io.Writer, table *Table, imageType ImageType) error {
+	switch imageType {
+	case SVG:
+		r := newSVGRenderer(ti.Size(table))
+		ti.Render(r, table)
+		_, err := r.WriteTo(w)
+		return err
+	case PDF:
+		r := newPDFRenderer(ti.Size(table), ti.pdfFontFile)
+		ti.Render(r, table)
+		_, err := r.WriteTo(w)
+		return err
 	}
-	ti.draw(img, table)
-	return img, nil
+	return Write(w, ti.drawTable(table), imageType)
+}
+
+// Save render table and save it to file
+func (ti *TableImage) Save(filepath string, rows []Row, caption *Cell, footer *Cell, imageType ImageType) error {
+	f, err := os.Create(filepath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return ti.Write(f, rows, caption, footer, imageType)
 }
 
-// Write witer image to io Writer
+// Write witer image to io Writer, SVG and PDF embed the raster image
 func Write(w io.Writer, img *image.RGBA, imageType ImageType) error {
 	switch imageType {
 	case JPEG:
 		return jpeg.Encode(w, img, nil)
 	case PNG:
 		return png.Encode(w, img)
+	case SVG:
+		return writeSVGImage(w, img)
+	case PDF:
+		return writePDFImage(w, img)
 	}
 	return errors.New("unknown image type")
 }
@@ -85,6 +184,11 @@ func (ti *TableImage) Size(table *Table) image.Point {
 	return rowsBounds.Add(ti.style.BorderSize())
 }
 
+// HasFont check if table base font is loaded, text isn't drawn without a font
+func (ti *TableImage) HasFont() bool {
+	return ti.style != nil && ti.style.Font != nil && ti.style.Font.Font != nil
+}
+
 // BorderSize get border width of tableimage
 func (ti *TableImage) BorderSize() image.Point {
 	border := image.ZP
@@ -101,21 +205,50 @@ func (ti *TableImage) innerStartPoint() image.Point {
 	return ti.style.InnerStart()
 }
 
-func (ti *TableImage) draw(img *image.RGBA, table *Table) {
+func (ti *TableImage) drawTable(table *Table) *image.RGBA {
+	bounds := ti.Size(table)
+	img := image.NewRGBA(image.Rect(0, 0, bounds.X, bounds.Y))
+	ti.Render(NewRasterRenderer(img), table)
+	return img
+}
+
+// Render draw table with renderer, the renderer canvas should be at least ti.Size(table)
+func (ti *TableImage) Render(r Renderer, table *Table) {
+	if ti.style != nil && ti.style.BgColor != "" {
+		size := ti.Size(table)
+		r.FillRect(image.Rect(0, 0, size.X, size.Y), ti.style.BgColor)
+	}
+	ti.draw(r, table)
+}
+
+func (ti *TableImage) draw(r Renderer, table *Table) {
 	startPoint := ti.innerStartPoint()
-	table.DrawCaption(img, startPoint)
+	table.DrawCaption(r, startPoint)
 	rowsStartPoint := table.RowsStartPoint()
 	rowsPt := image.Pt(startPoint.X, startPoint.Y+rowsStartPoint.Y)
+	cr, tagged := r.(CellRenderer)
 	for rowIdx, row := range table.Rows() {
+		if tagged {
+			cr.BeginRow(row.Header)
+		}
 		for cellIdx, cell := range row.Cells {
 			bounds := table.CellBounds(rowIdx, cellIdx)
 			bounds = bounds.Add(rowsPt)
-			cell.Draw(img, bounds)
+			if tagged {
+				cr.BeginCell()
+			}
+			cell.Draw(r, bounds)
+			if tagged {
+				cr.EndCell()
+			}
+		}
+		if tagged {
+			cr.EndRow()
 		}
 	}
 	rowsSize := table.RowsSize()
 	footerPt := image.Pt(startPoint.X, rowsPt.Y+rowsSize.Y)
-	table.DrawFooter(img, footerPt)
+	table.DrawFooter(r, footerPt)
 }
 
 // CacheImage cache image
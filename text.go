@@ -1,28 +1,27 @@
 package tableimage
 
 import (
-	"regexp"
-	"strconv"
-	"unicode"
-
 	"github.com/golang/freetype/truetype"
-	"github.com/mattn/go-runewidth"
+	"github.com/llgcode/draw2d"
 	"golang.org/x/image/font"
 )
 
-var (
-	reText = regexp.MustCompile(`<text(\s+?(?P<attrs>.+?))?(\s+)?>(?P<txt>.+?)</text>`)
-	reAttr = regexp.MustCompile(`(?P<attr>\w+)=["|'](?P<value>#?\w+)["|']`)
-)
-
 // Text string with width
 type Text struct {
-	Value   string
-	Width   int
-	Color   string
-	BgColor string
-	Padding int
-	Pos     [2]int
+	Value     string
+	Width     int
+	Color     string
+	BgColor   string
+	Padding   int
+	Pos       [2]int
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Strike    bool
+	Mono      bool
+	FontName  string
+	FontSize  float64
+	Font      *Font
 }
 
 // TextFromText create Text from Text
@@ -34,7 +33,21 @@ func TextFromText(value string, txt Text, fontFace font.Face) Text {
 
 // SameStyle check if two Text style is same
 func (t Text) SameStyle(t2 Text) bool {
-	return t.Color == t2.Color && t.BgColor == t2.BgColor && t.Padding == t2.Padding
+	return t.Color == t2.Color && t.BgColor == t2.BgColor && t.Padding == t2.Padding &&
+		t.Bold == t2.Bold && t.Italic == t2.Italic && t.Underline == t2.Underline && t.Strike == t2.Strike && t.Mono == t2.Mono &&
+		t.FontName == t2.FontName && t.FontSize == t2.FontSize && t.Font == t2.Font
+}
+
+// fontStyle draw2d font style bits of text run
+func (t Text) fontStyle() draw2d.FontStyle {
+	var style draw2d.FontStyle
+	if t.Bold {
+		style |= draw2d.FontStyleBold
+	}
+	if t.Italic {
+		style |= draw2d.FontStyleItalic
+	}
+	return style
 }
 
 // Word text array
@@ -49,185 +62,72 @@ func (w Word) Width() int {
 	return l
 }
 
-func wrap(s string, w int, fontFace font.Face, ignoreInlineStyle bool) ([]Word, int) {
-	segments := extractTexts(s, ignoreInlineStyle)
-	words := separateWords(segments, fontFace)
-	if w > 0 {
-		words = wrapWords(words, w, fontFace)
-	}
-	var maxWidth int
-	for _, w := range words {
-		if w.Width() > maxWidth {
-			maxWidth = w.Width()
-		}
-	}
-	return words, maxWidth
+// hardBreak check if line ends with a new line instead of being wrapped
+func (w Word) hardBreak() bool {
+	return len(w) > 0 && w[len(w)-1].Value == "\n"
 }
 
-// wrapWords wrap words to lines in w length
-func wrapWords(words []Word, w int, fontFace font.Face) []Word {
-	var (
-		retWords []Word
-		word     Word
-	)
-	for _, segs := range words {
-		for _, txt := range segs {
-			ww := int(stringWidth(txt.Value, fontFace))
-			if txt.Value == "\n" || word.Width()+ww > w {
-				retWords = append(retWords, word)
-				word = Word{}
-				if txt.Value != "\n" {
-					word = append(word, txt)
-				}
-				continue
-			}
-			if len(word) > 0 && word[len(word)-1].SameStyle(txt) {
-				lastWord := word[len(word)-1]
-				word[len(word)-1] = TextFromText(lastWord.Value+txt.Value, word[len(word)-1], fontFace)
-			} else {
-				word = append(word, txt)
-			}
-		}
-	}
-	if len(word) > 0 {
-		retWords = append(retWords, word)
-	}
-	return retWords
-}
+// faceCache font faces of text runs, keyed by run font
+type faceCache map[*Font]font.Face
 
-// separateWords seperate a string into words and not break word
-func separateWords(segments []Text, fontFace font.Face) []Word {
-	var (
-		words     []Word
-		wordTexts Word
-	)
-	for _, seg := range segments {
-		var segWord []rune
-		for _, r := range []rune(seg.Value) {
-			l := len(segWord)
-			cw := runewidth.RuneWidth(r)
-			segWordStr := string(segWord)
-			if cw == 0 {
-				if l > 0 {
-					wordTexts = append(wordTexts, TextFromText(segWordStr, seg, fontFace))
-					words = append(words, wordTexts)
-				}
-				wordTexts = Word{}
-				segWord = []rune{}
-				continue
-			} else if unicode.IsSpace(r) || unicode.IsPunct(r) || cw == 2 { // \n \t \s or double width add new word
-				if l > 0 {
-					wordTexts = append(wordTexts, TextFromText(segWordStr, seg, fontFace))
-				}
-				wordTexts = append(wordTexts, TextFromText(string(r), seg, fontFace))
-				words = append(words, wordTexts)
-				wordTexts = Word{}
-				segWord = []rune{}
-				continue
-			}
-			segWord = append(segWord, r)
-		}
-		if len(segWord) > 0 {
-			segWordStr := string(segWord)
-			wordTexts = append(wordTexts, TextFromText(segWordStr, seg, fontFace))
-		}
+func (c faceCache) face(f *Font) font.Face {
+	if f == nil {
+		return nil
 	}
-	if len(wordTexts) > 0 {
-		words = append(words, wordTexts)
+	if face, found := c[f]; found {
+		return face
 	}
-	return words
+	face := newFontFace(f.Font, f.Size)
+	c[f] = face
+	return face
 }
 
-func extractTexts(s string, ignoreInlineStyle bool) []Text {
-	txtL := len(s)
-	var segments []Text
-	matches := reText.FindAllStringSubmatchIndex(s, -1)
-	groupNames := reText.SubexpNames()
-	totalMatches := len(matches)
-	if totalMatches == 0 || ignoreInlineStyle {
-		return []Text{{
-			Value: s,
-		}}
+// wrap break text into lines in w length at unicode line break opportunities, words wider than w are hyphenated
+// if a hyphenator is set or broken between graphemes, w <= 0 puts every word on its own line
+func wrap(s string, w int, baseFont *Font, format TextFormat, hyphenator Hyphenator) ([]Word, int) {
+	faces := make(faceCache)
+	units := textUnits(s, baseFont, format, faces)
+	var lines []Word
+	if w > 0 {
+		lines = wrapUnits(units, w, hyphenator, faces)
+	} else {
+		lines = unitLines(units, faces)
 	}
-	for idx, locs := range matches {
-		if idx == 0 && locs[0] > 0 {
-			segments = append(segments, Text{
-				Pos:   [2]int{0, locs[0]},
-				Value: s[0:locs[0]],
-			})
-		} else if len(segments) > 0 && locs[0] > segments[idx-1].Pos[1] {
-			lastTxt := segments[idx-1]
-			segments = append(segments, Text{
-				Pos:   [2]int{lastTxt.Pos[1], locs[0]},
-				Value: s[lastTxt.Pos[1]:locs[0]],
-			})
-		}
-		text := extractText(s, groupNames, locs)
-		segments = append(segments, text)
-		if idx == totalMatches-1 && locs[1] < txtL {
-			segments = append(segments, Text{
-				Pos:   [2]int{locs[1], txtL},
-				Value: s[locs[1]:txtL],
-			})
+	var maxWidth int
+	for _, line := range lines {
+		if line.Width() > maxWidth {
+			maxWidth = line.Width()
 		}
 	}
-	return segments
+	return lines, maxWidth
 }
 
-func extractText(s string, groupNames []string, locs []int) Text {
-	text := Text{
-		Pos: [2]int{locs[0], locs[1]},
-	}
-	for j, name := range groupNames {
-		if j == 0 || name == "" {
-			continue
-		}
-		content := s[locs[j*2]:locs[j*2+1]]
-		if name == "attrs" {
-			attrs := extractAttrs(content)
-			for k, v := range attrs {
-				switch k {
-				case "color":
-					text.Color = v
-				case "bgcolor":
-					text.BgColor = v
-				case "padding":
-					text.Padding, _ = strconv.Atoi(v)
-				}
-			}
-		} else if name == "txt" {
-			text.Value = content
-		}
+// textUnits extract text runs with their own fonts and break them into units between line break opportunities
+func textUnits(s string, baseFont *Font, format TextFormat, faces faceCache) []lineUnit {
+	segments := extractTexts(s, format)
+	for idx, seg := range segments {
+		segments[idx].Font = baseFont.derive(seg)
 	}
-	return text
+	return breakUnits(segments, faces)
 }
 
-// extractAttrs extract text tag attributes
-func extractAttrs(s string) map[string]string {
-	matches := reAttr.FindAllStringSubmatch(s, -1)
-	groupNames := reAttr.SubexpNames()
-	ret := make(map[string]string, len(matches))
-	for _, m := range matches {
-		var (
-			k string
-			v string
-		)
-		for j, name := range groupNames {
-			if j == 0 || name == "" {
-				continue
-			}
-			switch name {
-			case "attr":
-				k = m[j]
-			case "value":
-				v = m[j]
-			}
-		}
-		if k != "" && v != "" {
-			ret[k] = v
-		}
+// extractTexts parse inline markup or markdown into styled text runs, malformed markup is kept as plain text
+func extractTexts(s string, format TextFormat) []Text {
+	switch format {
+	case Plain:
+		return []Text{{
+			Value: s,
+		}}
+	case Markdown:
+		return ParseMarkdown(s).Runs()
+	}
+	root, err := ParseMarkup(s)
+	if err != nil {
+		return []Text{{
+			Value: s,
+		}}
 	}
-	return ret
+	return root.Runs()
 }
 
 // MeasureString returns the rendered width and height of the specified text
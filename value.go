@@ -0,0 +1,268 @@
+package tableimage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValueFormat format spec rendering Cell.Value into text
+type ValueFormat struct {
+	// Locale locale of decimal and thousands separators and currency position like "en", "de-DE" or "fr", "en" by default
+	Locale string `json:"locale,omitempty"`
+	// Thousands group integer digits with locale thousands separator
+	Thousands bool `json:"thousands,omitempty"`
+	// Decimals fixed decimals count, shortest representation by default, 2 for currency
+	Decimals *int `json:"decimals,omitempty"`
+	// Currency currency symbol like "$" or "€", placed by locale
+	Currency string `json:"currency,omitempty"`
+	// Percent multiply value by 100 and append percent sign
+	Percent bool `json:"percent,omitempty"`
+	// Layout time layout of time values, time.RFC3339 by default, string values in RFC 3339 are parsed as time
+	Layout string `json:"layout,omitempty"`
+	// Location time zone name of time values like "UTC" or "Asia/Shanghai", time values keep their location by default
+	Location string `json:"location,omitempty"`
+}
+
+// NumberLocale number separators and currency position of a locale
+type NumberLocale struct {
+	// Decimal decimal separator
+	Decimal string
+	// Thousands thousands separator
+	Thousands string
+	// CurrencySuffix currency symbol follows the number
+	CurrencySuffix bool
+	// CurrencySpace currency symbol is separated from the number by a no-break space
+	CurrencySpace bool
+}
+
+// NumberLocales known locales by lowercase language or language-region tag, could be extended
+var NumberLocales = map[string]NumberLocale{
+	"en":    {Decimal: ".", Thousands: ","},
+	"zh":    {Decimal: ".", Thousands: ","},
+	"ja":    {Decimal: ".", Thousands: ","},
+	"ko":    {Decimal: ".", Thousands: ","},
+	"de":    {Decimal: ",", Thousands: ".", CurrencySuffix: true, CurrencySpace: true},
+	"de-ch": {Decimal: ".", Thousands: "'", CurrencySpace: true},
+	"es":    {Decimal: ",", Thousands: ".", CurrencySuffix: true, CurrencySpace: true},
+	"it":    {Decimal: ",", Thousands: ".", CurrencySuffix: true, CurrencySpace: true},
+	"nl":    {Decimal: ",", Thousands: ".", CurrencySpace: true},
+	"pt":    {Decimal: ",", Thousands: ".", CurrencySuffix: true, CurrencySpace: true},
+	"pt-br": {Decimal: ",", Thousands: ".", CurrencySpace: true},
+	"fr":    {Decimal: ",", Thousands: "\u00a0", CurrencySuffix: true, CurrencySpace: true},
+	"ru":    {Decimal: ",", Thousands: "\u00a0", CurrencySuffix: true, CurrencySpace: true},
+	"pl":    {Decimal: ",", Thousands: "\u00a0", CurrencySuffix: true, CurrencySpace: true},
+	"sv":    {Decimal: ",", Thousands: "\u00a0", CurrencySuffix: true, CurrencySpace: true},
+}
+
+// numberLocale locale by tag, falls back to language and then "en"
+func numberLocale(tag string) NumberLocale {
+	tag = strings.ToLower(strings.Replace(tag, "_", "-", -1))
+	if locale, found := NumberLocales[tag]; found {
+		return locale
+	}
+	if idx := strings.IndexByte(tag, '-'); idx > 0 {
+		if locale, found := NumberLocales[tag[:idx]]; found {
+			return locale
+		}
+	}
+	return NumberLocales["en"]
+}
+
+// FormatValue render value into text, numbers get separators, decimals, currency and percent,
+// time values are formatted with Layout, other values with fmt, nil is empty
+func (f ValueFormat) FormatValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if t, ok := f.timeValue(v); ok {
+		return f.formatTime(t)
+	}
+	if n, ok := numberValue(v); ok {
+		return f.formatNumber(n)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		return f.FormatValue(rv.Elem().Interface())
+	}
+	return fmt.Sprint(v)
+}
+
+func (f ValueFormat) timeValue(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case *time.Time:
+		if t != nil {
+			return *t, true
+		}
+	case string:
+		if f.Layout == "" {
+			return time.Time{}, false
+		}
+		if parsed, err := time.Parse(time.RFC3339, t); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (f ValueFormat) formatTime(t time.Time) string {
+	if f.Location != "" {
+		if loc, err := time.LoadLocation(f.Location); err == nil {
+			t = t.In(loc)
+		}
+	}
+	layout := f.Layout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return t.Format(layout)
+}
+
+// number integer or float value, integers keep their exact digits
+type number struct {
+	integer  bool
+	negative bool
+	digits   string
+	float    float64
+}
+
+// numberValue number from integer, float or json.Number values
+func numberValue(v interface{}) (number, bool) {
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			v = i
+		} else if digits := strings.TrimPrefix(n.String(), "-"); digits != "" && strings.Trim(digits, "0123456789") == "" {
+			// integers out of int64 range keep their digits
+			f, _ := n.Float64()
+			return number{
+				integer:  true,
+				negative: strings.HasPrefix(n.String(), "-"),
+				digits:   digits,
+				float:    f,
+			}, true
+		} else if f, err := n.Float64(); err == nil {
+			v = f
+		} else {
+			return number{}, false
+		}
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		n := number{
+			integer:  true,
+			negative: i < 0,
+			float:    float64(i),
+		}
+		n.digits = strings.TrimPrefix(strconv.FormatInt(i, 10), "-")
+		return n, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		return number{
+			integer: true,
+			digits:  strconv.FormatUint(u, 10),
+			float:   float64(u),
+		}, true
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return number{}, false
+		}
+		return number{
+			negative: f < 0,
+			float:    f,
+		}, true
+	}
+	return number{}, false
+}
+
+func (f ValueFormat) formatNumber(n number) string {
+	locale := numberLocale(f.Locale)
+	decimals := -1
+	if f.Decimals != nil {
+		decimals = *f.Decimals
+	} else if f.Currency != "" {
+		decimals = 2
+	}
+	value := n.float
+	if f.Percent {
+		value *= 100
+	}
+	if (!n.integer || f.Percent) && (math.IsNaN(value) || math.IsInf(value, 0)) {
+		// non-finite values have no digits to group
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	var digits string
+	switch {
+	case f.Percent:
+		n.negative = value < 0
+		digits = strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	case n.integer:
+		digits = n.digits
+		if decimals > 0 {
+			digits += "." + strings.Repeat("0", decimals)
+		}
+	default:
+		digits = strconv.FormatFloat(math.Abs(n.float), 'f', decimals, 64)
+	}
+	intPart, fracPart := digits, ""
+	if idx := strings.IndexByte(digits, '.'); idx >= 0 {
+		intPart, fracPart = digits[:idx], digits[idx+1:]
+	}
+	if f.Thousands {
+		intPart = groupDigits(intPart, locale.Thousands)
+	}
+	text := intPart
+	if fracPart != "" {
+		text += locale.Decimal + fracPart
+	}
+	if n.negative && strings.Trim(digits, "0.") != "" {
+		text = "-" + text
+	}
+	if f.Percent {
+		text += "%"
+	}
+	if f.Currency != "" {
+		var space string
+		if locale.CurrencySpace {
+			space = "\u00a0"
+		}
+		if locale.CurrencySuffix {
+			text += space + f.Currency
+		} else if strings.HasPrefix(text, "-") {
+			text = "-" + f.Currency + space + text[1:]
+		} else {
+			text = f.Currency + space + text
+		}
+	}
+	return text
+}
+
+// groupDigits insert separator between every 3 digits from the right
+func groupDigits(digits string, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for idx := head; idx < len(digits); idx += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[idx : idx+3])
+	}
+	return b.String()
+}
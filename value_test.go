@@ -0,0 +1,54 @@
+package tableimage
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFormatValue(t *testing.T) {
+	two := 2
+	zero := 0
+	ts := time.Date(2024, 3, 5, 18, 30, 0, 0, time.FixedZone("CST", 8*3600))
+	tests := []struct {
+		name   string
+		format ValueFormat
+		value  interface{}
+		want   string
+	}{
+		{"nil", ValueFormat{}, nil, ""},
+		{"integer", ValueFormat{}, 1234567, "1234567"},
+		{"float shortest", ValueFormat{}, 1234.5, "1234.5"},
+		{"thousands en", ValueFormat{Thousands: true}, -1234567, "-1,234,567"},
+		{"thousands de", ValueFormat{Locale: "de-DE", Thousands: true}, 1234567.25, "1.234.567,25"},
+		{"thousands de-ch", ValueFormat{Locale: "de-CH", Thousands: true}, 1234567.25, "1'234'567.25"},
+		{"thousands fr", ValueFormat{Locale: "fr", Thousands: true}, 1234567.25, "1\u00a0234\u00a0567,25"},
+		{"unknown locale", ValueFormat{Locale: "xx", Thousands: true}, 1234.5, "1,234.5"},
+		{"fixed decimals", ValueFormat{Decimals: &two}, 3.14159, "3.14"},
+		{"fixed decimals integer", ValueFormat{Decimals: &two}, 3, "3.00"},
+		{"zero decimals", ValueFormat{Decimals: &zero}, 2.5, "2"},
+		{"negative zero", ValueFormat{Decimals: &zero}, -0.2, "0"},
+		{"currency prefix", ValueFormat{Currency: "$", Thousands: true}, 1234.5, "$1,234.50"},
+		{"currency prefix negative", ValueFormat{Currency: "$"}, -5, "-$5.00"},
+		{"currency suffix", ValueFormat{Locale: "de", Currency: "€", Thousands: true}, -1234.5, "-1.234,50\u00a0€"},
+		{"currency prefix space", ValueFormat{Locale: "nl", Currency: "€"}, 7, "€\u00a07,00"},
+		{"percent", ValueFormat{Percent: true}, 0.125, "12.5%"},
+		{"percent decimals", ValueFormat{Percent: true, Decimals: &zero}, -0.256, "-26%"},
+		{"percent locale", ValueFormat{Locale: "de", Percent: true}, 0.125, "12,5%"},
+		{"percent overflow", ValueFormat{Percent: true, Thousands: true}, math.MaxFloat64, "+Inf"},
+		{"json number", ValueFormat{Thousands: true}, json.Number("12345678901234567890123"), "12,345,678,901,234,567,890,123"},
+		{"pointer", ValueFormat{Decimals: &two}, &two, "2.00"},
+		{"string", ValueFormat{Thousands: true}, "1234", "1234"},
+		{"time default layout", ValueFormat{}, ts, "2024-03-05T18:30:00+08:00"},
+		{"time layout", ValueFormat{Layout: "2006-01-02 15:04"}, ts, "2024-03-05 18:30"},
+		{"time location", ValueFormat{Layout: "2006-01-02 15:04 MST", Location: "UTC"}, ts, "2024-03-05 10:30 UTC"},
+		{"time string", ValueFormat{Layout: "Jan 2, 2006"}, "2024-03-05T18:30:00+08:00", "Mar 5, 2024"},
+		{"time string without layout", ValueFormat{}, "2024-03-05T18:30:00+08:00", "2024-03-05T18:30:00+08:00"},
+	}
+	for _, tt := range tests {
+		if got := tt.format.FormatValue(tt.value); got != tt.want {
+			t.Errorf("%s: FormatValue(%v) = %q, want %q", tt.name, tt.value, got, tt.want)
+		}
+	}
+}